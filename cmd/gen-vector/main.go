@@ -0,0 +1,89 @@
+// Command gen-vector captures a live engine run into a new enginetest
+// vector file: it pulls a symbol's stored market snapshots for a date
+// range from the live store, replays them through a fresh engine (exactly
+// like go test ./internal/engine/... does), and records the events that
+// replay produced as the vector's expected_events. Regenerate a vector
+// whenever a deliberate rule change should become the new baseline.
+//
+// Usage:
+//
+//	go run ./cmd/gen-vector -store data/app.db -symbol sh000001 \
+//		-start 2026-07-01 -end 2026-07-01 \
+//		-out internal/engine/testdata/vectors/sh000001_20260701.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"ai-trading-assistant/internal/engine"
+	"ai-trading-assistant/internal/engine/enginetest"
+	"ai-trading-assistant/internal/store"
+)
+
+func main() {
+	dsn := flag.String("store", "", "DSN of the live store to read snapshots from (bare path, sqlite://..., postgres://...)")
+	symbol := flag.String("symbol", "", "symbol to capture")
+	start := flag.String("start", "", "start date, inclusive (YYYY-MM-DD, Asia/Shanghai)")
+	end := flag.String("end", "", "end date, inclusive (YYYY-MM-DD, Asia/Shanghai)")
+	name := flag.String("name", "", "vector name (defaults to <symbol>_<start>_<end>)")
+	out := flag.String("out", "", "path to write the generated vector JSON file")
+	flag.Parse()
+
+	if *dsn == "" || *symbol == "" || *start == "" || *end == "" || *out == "" {
+		log.Fatalf("usage: gen-vector -store <dsn> -symbol <symbol> -start <date> -end <date> -out <file>")
+	}
+
+	v, err := capture(*dsn, *symbol, *start, *end)
+	if err != nil {
+		log.Fatalf("capture vector: %v", err)
+	}
+	if *name != "" {
+		v.Name = *name
+	} else {
+		v.Name = fmt.Sprintf("%s_%s_%s", *symbol, *start, *end)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Fatalf("marshal vector: %v", err)
+	}
+	if err := os.WriteFile(*out, append(data, '\n'), 0o644); err != nil {
+		log.Fatalf("write vector: %v", err)
+	}
+	log.Printf("wrote vector %q (%d snapshots, %d expected events) to %s", v.Name, len(v.Snapshots), len(v.ExpectedEvents), *out)
+}
+
+func capture(dsn, symbol, start, end string) (enginetest.Vector, error) {
+	liveStore, err := store.Open(dsn)
+	if err != nil {
+		return enginetest.Vector{}, fmt.Errorf("open store: %w", err)
+	}
+	defer liveStore.Close()
+
+	snaps, err := liveStore.QueryMarketSnapshotsRange(symbol, start, end)
+	if err != nil {
+		return enginetest.Vector{}, fmt.Errorf("query snapshots: %w", err)
+	}
+	if len(snaps) == 0 {
+		return enginetest.Vector{}, fmt.Errorf("no snapshots found for %s between %s and %s", symbol, start, end)
+	}
+
+	v := enginetest.Vector{Snapshots: snaps}
+	// Same engine.Config as TestVectors in internal/engine/vectors_test.go,
+	// so the captured expected_events are what that test reproduces.
+	cfg := engine.Config{
+		IndexRisk: engine.IndexRiskConfig{Symbol: "sh000001", MedPct: 3, HighPct: 5},
+	}
+	events, err := enginetest.Replay(cfg, v)
+	if err != nil {
+		return enginetest.Vector{}, fmt.Errorf("replay: %w", err)
+	}
+	for _, e := range events {
+		v.ExpectedEvents = append(v.ExpectedEvents, enginetest.ToExpected(e))
+	}
+	return v, nil
+}
@@ -0,0 +1,121 @@
+// Command genvectors regenerates riskagent/testvectors fixtures from a
+// corpus directory. The corpus holds two kinds of input files:
+//
+//   - *.input.json  a raw riskagent.EventInput; FallbackDecision is run
+//     against it and the result is captured as the vector's expectation.
+//   - *.llm.txt      raw (possibly malformed) LLM output text; ParseDecisionText
+//     is run against it and expect_parse is set to whether it succeeded.
+//
+// Usage:
+//
+//	go run ./cmd/genvectors -corpus ./corpus -out ./internal/riskagent/testvectors/testdata/vectors/generated.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"ai-trading-assistant/internal/riskagent"
+	"ai-trading-assistant/internal/riskagent/testvectors"
+)
+
+func main() {
+	corpus := flag.String("corpus", "", "directory of *.input.json / *.llm.txt corpus files")
+	out := flag.String("out", "", "path to write the generated vectors JSON file")
+	flag.Parse()
+
+	if *corpus == "" || *out == "" {
+		log.Fatalf("usage: genvectors -corpus <dir> -out <file>")
+	}
+
+	vectors, err := generate(*corpus)
+	if err != nil {
+		log.Fatalf("generate vectors: %v", err)
+	}
+
+	data, err := json.MarshalIndent(vectors, "", "  ")
+	if err != nil {
+		log.Fatalf("marshal vectors: %v", err)
+	}
+	if err := os.WriteFile(*out, append(data, '\n'), 0o644); err != nil {
+		log.Fatalf("write vectors: %v", err)
+	}
+	log.Printf("wrote %d vectors to %s", len(vectors), *out)
+}
+
+func generate(corpusDir string) ([]testvectors.Vector, error) {
+	entries, err := os.ReadDir(corpusDir)
+	if err != nil {
+		return nil, fmt.Errorf("read corpus dir: %w", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var vectors []testvectors.Vector
+	for _, name := range names {
+		path := filepath.Join(corpusDir, name)
+		switch {
+		case strings.HasSuffix(name, ".input.json"):
+			v, err := vectorFromInput(path, name)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			vectors = append(vectors, v)
+		case strings.HasSuffix(name, ".llm.txt"):
+			v, err := vectorFromLLMText(path, name)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			vectors = append(vectors, v)
+		}
+	}
+	return vectors, nil
+}
+
+func vectorFromInput(path, name string) (testvectors.Vector, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return testvectors.Vector{}, err
+	}
+	var in riskagent.EventInput
+	if err := json.Unmarshal(raw, &in); err != nil {
+		return testvectors.Vector{}, fmt.Errorf("parse event input: %w", err)
+	}
+
+	decision := riskagent.FallbackDecision(in)
+	return testvectors.Vector{
+		Name:        strings.TrimSuffix(name, ".input.json"),
+		Input:       &in,
+		ExpectParse: true,
+		Expect: testvectors.Expectation{
+			SeverityOneOf:  []string{decision.Severity},
+			RiskLevelOneOf: []int{decision.RiskLevel},
+		},
+	}, nil
+}
+
+func vectorFromLLMText(path, name string) (testvectors.Vector, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return testvectors.Vector{}, err
+	}
+	text := string(raw)
+	_, parseErr := riskagent.ParseDecisionText(text)
+
+	return testvectors.Vector{
+		Name:        strings.TrimSuffix(name, ".llm.txt"),
+		RawLLMText:  text,
+		ExpectParse: parseErr == nil,
+	}, nil
+}
@@ -1,28 +1,36 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net"
 	"time"
 
 	"ai-trading-assistant/internal/alert"
 	"ai-trading-assistant/internal/api"
+	apigrpc "ai-trading-assistant/internal/api/grpc"
+	"ai-trading-assistant/internal/api/grpc/apiv1pb"
+	"ai-trading-assistant/internal/api/service"
 	"ai-trading-assistant/internal/config"
 	"ai-trading-assistant/internal/engine"
 	"ai-trading-assistant/internal/market"
+	"ai-trading-assistant/internal/notifier"
 	"ai-trading-assistant/internal/planagent"
 	"ai-trading-assistant/internal/push/dingtalk"
 	"ai-trading-assistant/internal/riskagent"
 	"ai-trading-assistant/internal/store"
 
 	"github.com/cloudwego/hertz/pkg/app/server"
+	"google.golang.org/grpc"
 )
 
 func main() {
-	cfg, err := config.Load("configs/app.yaml")
+	mgr, err := config.NewManager("configs/app.yaml")
 	if err != nil {
 		log.Fatalf("config error: %v", err)
 	}
+	cfg := mgr.Current()
 
 	addr := fmt.Sprintf(":%d", cfg.Server.Port)
 	h := server.Default(server.WithHostPorts(addr))
@@ -33,7 +41,7 @@ func main() {
 		time.Duration(cfg.Push.Dingtalk.TimeoutMs)*time.Millisecond,
 	)
 
-	st, err := store.Open(cfg.Store.Sqlite.Path)
+	st, err := store.Open(cfg.Store.ResolveDSN())
 	if err != nil {
 		log.Fatalf("store error: %v", err)
 	}
@@ -43,15 +51,8 @@ func main() {
 		}
 	}()
 
-	alertSvc := alert.NewService(dt, st, alert.Config{
-		RateLimit: alert.RateLimitConfig{
-			PerMinute: cfg.Alert.RateLimit.PerMinute,
-			Burst:     cfg.Alert.RateLimit.Burst,
-		},
-		DedupWindow:       time.Duration(cfg.Alert.Dedup.WindowSec) * time.Second,
-		MergeWindow:       time.Duration(cfg.Alert.Merge.WindowSec) * time.Second,
-		LowDigestInterval: time.Duration(cfg.Alert.Digest.LowIntervalSec) * time.Second,
-	})
+	coordinator := buildClusterCoordinator(cfg)
+	alertSvc := alert.NewService(buildAlertNotifiers(cfg, dt), coordinator, st, buildAlertConfig(cfg))
 
 	var agent *riskagent.Agent
 	if cfg.RiskAgent.Enabled {
@@ -66,17 +67,142 @@ func main() {
 		})
 	}
 
+	var notifierSvc *notifier.Service
+	if cfg.Notifications.Lark.Enabled || cfg.Notifications.Webhook.Enabled ||
+		cfg.Notifications.Telegram.Enabled || cfg.Notifications.Slack.Enabled {
+		var notifiers []notifier.Notifier
+		if cfg.Notifications.Lark.Enabled {
+			notifiers = append(notifiers, notifier.NewSeverityFilter(notifier.NewLarkClient(
+				cfg.Notifications.Lark.Webhook,
+				cfg.Notifications.Lark.Secret,
+				time.Duration(cfg.Notifications.Lark.TimeoutMs)*time.Millisecond,
+			), cfg.Notifications.Lark.MinSeverity))
+		}
+		if cfg.Notifications.Webhook.Enabled {
+			notifiers = append(notifiers, notifier.NewSeverityFilter(notifier.NewWebhookNotifier(
+				cfg.Notifications.Webhook.URL,
+				time.Duration(cfg.Notifications.Webhook.TimeoutMs)*time.Millisecond,
+			), cfg.Notifications.Webhook.MinSeverity))
+		}
+		if cfg.Notifications.Telegram.Enabled {
+			notifiers = append(notifiers, notifier.NewSeverityFilter(notifier.NewTelegramNotifier(
+				cfg.Notifications.Telegram.BotToken,
+				cfg.Notifications.Telegram.ChatID,
+				time.Duration(cfg.Notifications.Telegram.TimeoutMs)*time.Millisecond,
+			), cfg.Notifications.Telegram.MinSeverity))
+		}
+		if cfg.Notifications.Slack.Enabled {
+			notifiers = append(notifiers, notifier.NewSeverityFilter(notifier.NewSlackNotifier(
+				cfg.Notifications.Slack.WebhookURL,
+				time.Duration(cfg.Notifications.Slack.TimeoutMs)*time.Millisecond,
+			), cfg.Notifications.Slack.MinSeverity))
+		}
+		notifierSvc = notifier.NewService(notifier.Config{
+			DedupWindow:     time.Duration(cfg.Notifications.DedupWindowSec) * time.Second,
+			MinSendInterval: time.Duration(cfg.Notifications.MinIntervalSec) * time.Second,
+			MaxRetries:      cfg.Notifications.MaxRetries,
+			RetryBackoff:    time.Duration(cfg.Notifications.RetryBackoffMs) * time.Millisecond,
+		}, st, notifiers...)
+	}
+
+	instrumentSvc := market.NewInstrumentService(nil)
 	planAgent := planagent.New(planagent.Config{
-		Enabled:    cfg.PlanAgent.Enabled,
-		Model:      cfg.PlanAgent.Model,
-		APIKey:     cfg.PlanAgent.APIKey,
-		BaseURL:    cfg.PlanAgent.BaseURL,
-		ByAzure:    cfg.PlanAgent.ByAzure,
-		APIVersion: cfg.PlanAgent.APIVersion,
-		TimeoutMs:  cfg.PlanAgent.TimeoutMs,
-	})
+		Enabled:           cfg.PlanAgent.Enabled,
+		Model:             cfg.PlanAgent.Model,
+		APIKey:            cfg.PlanAgent.APIKey,
+		BaseURL:           cfg.PlanAgent.BaseURL,
+		ByAzure:           cfg.PlanAgent.ByAzure,
+		APIVersion:        cfg.PlanAgent.APIVersion,
+		TimeoutMs:         cfg.PlanAgent.TimeoutMs,
+		MaxRepairAttempts: cfg.PlanAgent.MaxRepairAttempts,
+	}, instrumentSvc)
 
-	eng := engine.New(engine.Config{
+	eng := engine.New(buildEngineConfig(cfg), st, alertSvc, agent, notifierSvc)
+
+	providers := buildMarketProviders(cfg.Market.Providers)
+	for _, gc := range cfg.Market.GenericProviders {
+		providers = append(providers, market.NewGenericRESTProvider(market.GenericRESTConfig{
+			Name:          gc.Name,
+			URLTemplate:   gc.URLTemplate,
+			PricePath:     gc.PricePath,
+			ChangePctPath: gc.ChangePctPath,
+			VolumePath:    gc.VolumePath,
+			NamePath:      gc.NamePath,
+			TimeoutMs:     gc.TimeoutMs,
+		}))
+	}
+	if cfg.Market.RateLimitPerSec > 0 {
+		for i, p := range providers {
+			providers[i] = market.NewRateLimitedProvider(p, cfg.Market.RateLimitPerSec, cfg.Market.RateLimitBurst)
+		}
+	}
+	mktProvider := market.NewMultiProviderWithConfig(market.MultiProviderConfig{
+		Strategy:   parseStrategy(cfg.Market.Strategy),
+		TieBreaker: parseTieBreaker(cfg.Market.TieBreaker),
+	}, providers...)
+	mktSvc := market.NewService(mktProvider, time.Duration(cfg.Market.MinRequestIntervalMs)*time.Millisecond, st, eng)
+
+	switch cfg.Market.Mode {
+	case "stream", "hybrid":
+		var fallback market.MarketProvider
+		if cfg.Market.Mode == "hybrid" {
+			fallback = mktProvider
+		}
+		streamProviders := buildStreamProviders(cfg.Market.StreamProviders)
+		sp := market.NewMultiStreamProvider(fallback, market.MultiStreamConfig{
+			RingSize:      cfg.Market.Stream.RingSize,
+			ReconnectBase: time.Duration(cfg.Market.Stream.ReconnectBaseMs) * time.Millisecond,
+			ReconnectMax:  time.Duration(cfg.Market.Stream.ReconnectMaxMs) * time.Millisecond,
+			FallbackPoll:  time.Duration(cfg.Market.Stream.FallbackPollSec) * time.Second,
+			StaleAfter:    time.Duration(cfg.Market.Stream.StaleAfterSec) * time.Second,
+			Sink:          mktSvc,
+		}, streamProviders...)
+		if len(cfg.Market.Symbols) > 0 {
+			go sp.Run(context.Background(), cfg.Market.Symbols)
+		}
+	default:
+		go runReloadSupervisor(context.Background(), mgr, eng, alertSvc, mktSvc)
+	}
+
+	go func() {
+		if err := mgr.Watch(context.Background()); err != nil {
+			log.Printf("config watch error: %v", err)
+		}
+	}()
+
+	api.RegisterRoutes(h, dt, alertSvc, st, mktSvc, cfg.Market.Symbols, eng, agent, planAgent)
+	log.Printf("route registered: POST /api/v1/test/risk/ping")
+	log.Printf("route registered: POST /api/v1/test/risk/eval")
+
+	if cfg.GRPC.Enabled {
+		svc := service.New(dt, alertSvc, st, mktSvc, eng, agent, planAgent, cfg.Market.Symbols)
+		grpcAddr := fmt.Sprintf(":%d", cfg.GRPC.Port)
+		lis, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			log.Fatalf("grpc listen error: %v", err)
+		}
+		grpcServer := grpc.NewServer(grpc.ForceServerCodec(apiv1pb.Codec))
+		apiv1pb.RegisterApiV1Server(grpcServer, apigrpc.New(svc))
+		go func() {
+			log.Printf("grpc server starting on %s", grpcAddr)
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Fatalf("grpc server error: %v", err)
+			}
+		}()
+	}
+
+	log.Printf("server starting on %s (log.level=%s)", addr, cfg.Log.Level)
+	if err := h.Run(); err != nil {
+		log.Fatalf("server run error: %v", err)
+	}
+}
+
+// buildEngineConfig translates config.Config's engine section into
+// engine.Config. It is shared by the initial engine.New call and by
+// runReloadSupervisor's eng.SetConfig calls, so a config.Manager reload
+// applies exactly the same translation the process started with.
+func buildEngineConfig(cfg *config.Config) engine.Config {
+	return engine.Config{
 		IndexRisk: engine.IndexRiskConfig{
 			Symbol:  cfg.Engine.IndexRisk.Symbol,
 			MedPct:  cfg.Engine.IndexRisk.MedPct,
@@ -87,6 +213,11 @@ func main() {
 			MedPct:    cfg.Engine.PanicDrop.MedPct,
 			HighPct:   cfg.Engine.PanicDrop.HighPct,
 		},
+		VolAdj: engine.VolAdjConfig{
+			ZWindowPoints: cfg.Engine.VolAdj.ZWindowPoints,
+			MedSigma:      cfg.Engine.VolAdj.MedSigma,
+			HighSigma:     cfg.Engine.VolAdj.HighSigma,
+		},
 		VolumeSpike: engine.VolumeSpikeConfig{
 			MaPoints: cfg.Engine.VolumeSpike.MaPoints,
 			Ratio:    cfg.Engine.VolumeSpike.Ratio,
@@ -95,33 +226,232 @@ func main() {
 			Levels:   cfg.Engine.KeyBreakDown.Levels,
 			Priority: cfg.Engine.KeyBreakDown.Priority,
 		},
+		NarrowRange: engine.NarrowRangeConfig{
+			N:            cfg.Engine.NarrowRange.N,
+			BarPeriodSec: cfg.Engine.NarrowRange.BarPeriodSec,
+			AtrPeriod:    cfg.Engine.NarrowRange.AtrPeriod,
+			AvgRangeN:    cfg.Engine.NarrowRange.AvgRangeN,
+			HighAtrPct:   cfg.Engine.NarrowRange.HighAtrPct,
+		},
 		CooldownSec: engine.CooldownConfig{
 			IndexRisk:    cfg.Engine.CooldownSec.IndexRisk,
 			PanicDrop:    cfg.Engine.CooldownSec.PanicDrop,
+			VolAdj:       cfg.Engine.CooldownSec.VolAdj,
 			VolumeSpike:  cfg.Engine.CooldownSec.VolumeSpike,
 			KeyBreakDown: cfg.Engine.CooldownSec.KeyBreakDown,
+			NarrowRange:  cfg.Engine.CooldownSec.NarrowRange,
 		},
 		WindowMaxKeep: cfg.Engine.WindowMaxKeep,
-	}, st, alertSvc, agent)
+	}
+}
 
-	mktProvider := market.NewMultiProvider(
-		market.NewEastmoneyProvider(5*time.Second),
-		market.NewSinaProvider(5*time.Second),
-	)
-	mktSvc := market.NewService(mktProvider, time.Duration(cfg.Market.MinRequestIntervalMs)*time.Millisecond, st, eng)
+// buildAlertConfig translates config.Config's alert section into
+// alert.Config, shared by the initial alert.NewService call and by
+// runReloadSupervisor's alertSvc.SetConfig calls.
+func buildAlertConfig(cfg *config.Config) alert.Config {
+	return alert.Config{
+		RateLimit: alert.RateLimitConfig{
+			PerMinute:            cfg.Alert.RateLimit.PerMinute,
+			Burst:                cfg.Alert.RateLimit.Burst,
+			GroupWeights:         cfg.Alert.RateLimit.GroupWeights,
+			LowReservationPct:    cfg.Alert.RateLimit.LowReservationPct,
+			HighBorrowCeilingPct: cfg.Alert.RateLimit.HighBorrowCeilingPct,
+		},
+		DedupWindow:       time.Duration(cfg.Alert.Dedup.WindowSec) * time.Second,
+		MergeWindow:       time.Duration(cfg.Alert.Merge.WindowSec) * time.Second,
+		LowDigestInterval: time.Duration(cfg.Alert.Digest.LowIntervalSec) * time.Second,
+		Routing:           cfg.Alert.Routing,
+		DefaultChannels:   cfg.Alert.DefaultChannels,
+	}
+}
 
-	if cfg.Market.PollIntervalSec > 0 && len(cfg.Market.Symbols) > 0 {
-		go func() {
-			mktSvc.PollLoop(cfg.Market.Symbols, time.Duration(cfg.Market.PollIntervalSec)*time.Second)
-		}()
+// buildClusterCoordinator returns an alert.NoopCoordinator when clustering
+// is disabled (the default), or a memberlist-backed coordinator that joins
+// cfg.Alert.Cluster.Seeds in the background. Join errors are logged, not
+// fatal: a replica that can't reach any seed still serves alerts correctly
+// on its own, just without cross-replica dedup/rate-limit sharing.
+func buildClusterCoordinator(cfg *config.Config) alert.ClusterCoordinator {
+	if !cfg.Alert.Cluster.Enabled {
+		return alert.NewNoopCoordinator()
 	}
+	coordinator, err := alert.NewMemberlistCoordinator(alert.ClusterConfig{
+		NodeName: cfg.Alert.Cluster.NodeName,
+		BindAddr: cfg.Alert.Cluster.BindAddr,
+		BindPort: cfg.Alert.Cluster.BindPort,
+		Seeds:    cfg.Alert.Cluster.Seeds,
+	})
+	if err != nil {
+		log.Printf("cluster coordinator create error: %v, falling back to single-node", err)
+		return alert.NewNoopCoordinator()
+	}
+	go func() {
+		if err := coordinator.Join(context.Background()); err != nil {
+			log.Printf("cluster coordinator join error: %v", err)
+		}
+	}()
+	return coordinator
+}
 
-	api.RegisterRoutes(h, dt, alertSvc, st, mktSvc, cfg.Market.Symbols, eng, agent, planAgent)
-	log.Printf("route registered: POST /api/v1/test/risk/ping")
-	log.Printf("route registered: POST /api/v1/test/risk/eval")
+// buildAlertNotifiers wires the DingTalk client plus any enabled channels
+// under Alert.Channels into the registry alert.NewService fans requests out
+// to. Unlike the rest of alert.Config, this registry is set once at
+// construction: live connection objects (HTTP clients, SMTP credentials)
+// aren't part of SetConfig's reloadable state, matching how market.Service's
+// provider chain is also built once and left out of hot-reload scope.
+func buildAlertNotifiers(cfg *config.Config, dt *dingtalk.Client) map[string]alert.Notifier {
+	notifiers := map[string]alert.Notifier{
+		"dingtalk": alert.NewDingTalkNotifier(dt),
+	}
+	if cfg.Alert.Channels.Lark.Enabled {
+		notifiers["lark"] = alert.NewSeverityFilter(alert.NewLarkNotifier(
+			cfg.Alert.Channels.Lark.Webhook,
+			cfg.Alert.Channels.Lark.Secret,
+			time.Duration(cfg.Alert.Channels.Lark.TimeoutMs)*time.Millisecond,
+		), cfg.Alert.Channels.Lark.MinSeverity)
+	}
+	if cfg.Alert.Channels.Slack.Enabled {
+		notifiers["slack"] = alert.NewSeverityFilter(alert.NewSlackNotifier(
+			cfg.Alert.Channels.Slack.WebhookURL,
+			time.Duration(cfg.Alert.Channels.Slack.TimeoutMs)*time.Millisecond,
+		), cfg.Alert.Channels.Slack.MinSeverity)
+	}
+	if cfg.Alert.Channels.Webhook.Enabled {
+		notifiers["webhook"] = alert.NewSeverityFilter(alert.NewWebhookNotifier(
+			cfg.Alert.Channels.Webhook.URL,
+			cfg.Alert.Channels.Webhook.Secret,
+			time.Duration(cfg.Alert.Channels.Webhook.TimeoutMs)*time.Millisecond,
+		), cfg.Alert.Channels.Webhook.MinSeverity)
+	}
+	if cfg.Alert.Channels.SMTP.Enabled {
+		notifiers["smtp"] = alert.NewSMTPNotifier(
+			cfg.Alert.Channels.SMTP.Host,
+			cfg.Alert.Channels.SMTP.Port,
+			cfg.Alert.Channels.SMTP.Username,
+			cfg.Alert.Channels.SMTP.Password,
+			cfg.Alert.Channels.SMTP.From,
+			cfg.Alert.Channels.SMTP.To,
+		)
+	}
+	return notifiers
+}
 
-	log.Printf("server starting on %s (log.level=%s)", addr, cfg.Log.Level)
-	if err := h.Run(); err != nil {
-		log.Fatalf("server run error: %v", err)
+// runReloadSupervisor applies every config.Manager reload to the engine,
+// alert service, and market service without restarting the process, and
+// restarts the poll loop goroutine whenever the symbols or poll interval it
+// was started with change. It only runs for the default (poll) market mode;
+// stream/hybrid mode's provider isn't wired for hot-reload here.
+func runReloadSupervisor(ctx context.Context, mgr *config.Manager, eng *engine.Engine, alertSvc *alert.Service, mktSvc *market.Service) {
+	var pollCancel context.CancelFunc
+	restartPollLoop := func(cfg *config.Config) {
+		if pollCancel != nil {
+			pollCancel()
+			pollCancel = nil
+		}
+		if cfg.Market.PollIntervalSec <= 0 || len(cfg.Market.Symbols) == 0 {
+			return
+		}
+		var pollCtx context.Context
+		pollCtx, pollCancel = context.WithCancel(ctx)
+		go mktSvc.PollLoop(pollCtx, cfg.Market.Symbols, time.Duration(cfg.Market.PollIntervalSec)*time.Second)
+	}
+
+	cfg := mgr.Current()
+	restartPollLoop(cfg)
+	mktSvc.SetMinInterval(time.Duration(cfg.Market.MinRequestIntervalMs) * time.Millisecond)
+
+	reloads := mgr.Subscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			if pollCancel != nil {
+				pollCancel()
+			}
+			return
+		case next, ok := <-reloads:
+			if !ok {
+				return
+			}
+			eng.SetConfig(buildEngineConfig(next))
+			alertSvc.SetConfig(buildAlertConfig(next))
+			mktSvc.SetMinInterval(time.Duration(next.Market.MinRequestIntervalMs) * time.Millisecond)
+			restartPollLoop(next)
+		}
+	}
+}
+
+// buildMarketProviders builds the ordered list of built-in providers from
+// config. An empty specs list falls back to the long-standing default
+// (eastmoney, sina, tencent — all free, tokenless feeds) so existing configs
+// keep working unchanged.
+func buildMarketProviders(specs []config.ProviderSpecConfig) []market.MarketProvider {
+	if len(specs) == 0 {
+		return []market.MarketProvider{
+			market.NewEastmoneyProvider(5 * time.Second),
+			market.NewSinaProvider(5 * time.Second),
+			market.NewTencentProvider(5 * time.Second),
+		}
+	}
+
+	var providers []market.MarketProvider
+	for _, spec := range specs {
+		if !spec.Enabled {
+			continue
+		}
+		timeout := time.Duration(spec.TimeoutMs) * time.Millisecond
+		switch spec.Name {
+		case "eastmoney":
+			providers = append(providers, market.NewEastmoneyProvider(timeout))
+		case "sina":
+			providers = append(providers, market.NewSinaProvider(timeout))
+		case "tencent":
+			providers = append(providers, market.NewTencentProvider(timeout))
+		case "tushare":
+			providers = append(providers, market.NewTushareProvider(spec.Token, timeout))
+		default:
+			log.Printf("market: unknown provider %q in config, skipping", spec.Name)
+		}
+	}
+	return providers
+}
+
+// buildStreamProviders builds the built-in StreamingProviders named in
+// config, in order. Unknown names are logged and skipped, the same way
+// buildMarketProviders handles an unrecognized polled provider name.
+func buildStreamProviders(names []string) []market.StreamingProvider {
+	var providers []market.StreamingProvider
+	for _, name := range names {
+		switch name {
+		case "binance-ws":
+			providers = append(providers, market.NewBinanceWSProvider(false))
+		case "binance-futures-ws":
+			providers = append(providers, market.NewBinanceWSProvider(true))
+		case "okx-ws":
+			providers = append(providers, market.NewOKXWSProvider(false))
+		case "okx-futures-ws":
+			providers = append(providers, market.NewOKXWSProvider(true))
+		default:
+			log.Printf("market: unknown stream provider %q in config, skipping", name)
+		}
+	}
+	return providers
+}
+
+func parseStrategy(s string) market.Strategy {
+	switch s {
+	case "race":
+		return market.StrategyRace
+	case "quorum":
+		return market.StrategyQuorum
+	case "hedged":
+		return market.StrategyHedged
+	default:
+		return market.StrategyFailover
+	}
+}
+
+func parseTieBreaker(s string) market.TieBreaker {
+	if s == "freshest" {
+		return market.TieBreakerFreshest
 	}
+	return market.TieBreakerConsensus
 }
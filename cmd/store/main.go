@@ -0,0 +1,75 @@
+// Command store manages the schema migrations under
+// internal/store/migrations/ (sqlite or postgres, whichever backend the
+// configured store DSN selects) directly, without starting the HTTP/gRPC
+// server.
+//
+// Usage:
+//
+//	go run ./cmd/store migrate up
+//	go run ./cmd/store migrate down
+//	go run ./cmd/store migrate status
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"ai-trading-assistant/internal/config"
+	"ai-trading-assistant/internal/store"
+)
+
+func main() {
+	configPath := flag.String("config", "configs/app.yaml", "path to app config (used for the store DSN)")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) != 2 || args[0] != "migrate" {
+		log.Fatalf("usage: store migrate up|down|status")
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("config error: %v", err)
+	}
+
+	st, err := store.Open(cfg.Store.ResolveDSN())
+	if err != nil {
+		log.Fatalf("store error: %v", err)
+	}
+	defer func() {
+		if err := st.Close(); err != nil {
+			log.Printf("store close error: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	switch args[1] {
+	case "up":
+		if err := st.Migrate(ctx, store.DirectionUp); err != nil {
+			log.Fatalf("migrate up: %v", err)
+		}
+		fmt.Println("migrated up")
+	case "down":
+		if err := st.Migrate(ctx, store.DirectionDown); err != nil {
+			log.Fatalf("migrate down: %v", err)
+		}
+		fmt.Println("migrated down")
+	case "status":
+		statuses, err := st.MigrationStatus(ctx)
+		if err != nil {
+			log.Fatalf("migration status: %v", err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied at " + s.AppliedAt
+			}
+			fmt.Fprintf(os.Stdout, "%06d_%s: %s\n", s.Version, s.Name, state)
+		}
+	default:
+		log.Fatalf("usage: store migrate up|down|status")
+	}
+}
@@ -0,0 +1,104 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AlertmanagerPayload is the webhook body a Prometheus Alertmanager receiver
+// POSTs: https://prometheus.io/docs/alerting/latest/configuration/#webhook_config
+type AlertmanagerPayload struct {
+	Status string              `json:"status"`
+	Alerts []AlertmanagerAlert `json:"alerts"`
+}
+
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// HandleAlertmanagerWebhook translates and dispatches every alert in an
+// Alertmanager webhook payload through the normal Handle path, so pointing
+// an existing Alertmanager receiver at this service needs no config changes
+// on the Alertmanager side. A resolved alert cancels any pending merge
+// state for its key before its own resolution notice is handled, so a
+// firing alert merged moments earlier doesn't get flushed after the fact.
+func (s *Service) HandleAlertmanagerWebhook(ctx context.Context, payload AlertmanagerPayload) []Result {
+	results := make([]Result, 0, len(payload.Alerts))
+	for _, a := range payload.Alerts {
+		req := TranslateAlertmanagerAlert(a)
+		if a.Status == "resolved" {
+			s.CancelMerge(req.MergeKey)
+		}
+		results = append(results, s.Handle(ctx, req))
+	}
+	return results
+}
+
+// TranslateAlertmanagerAlert converts one Alertmanager alert into the
+// AlertRequest Service.Handle expects. Severity maps critical->high,
+// warning->med, info->low (anything else falls back to med). Fingerprint
+// becomes DedupKey, since Alertmanager already resends firing alerts on its
+// own group_interval and we don't want to re-fire on every resend — a
+// resolved alert gets a ":resolved" suffixed DedupKey instead of reusing the
+// firing alert's, since otherwise isDeduped would treat the resolution
+// notice as just another resend of the still-deduped firing alert and
+// silently drop it. alertname+namespace becomes MergeKey so a batch of
+// related alerts collapses into one merged notification.
+func TranslateAlertmanagerAlert(a AlertmanagerAlert) AlertRequest {
+	title := a.Labels["alertname"]
+	if summary := a.Annotations["summary"]; summary != "" {
+		title = summary
+	}
+	dedupKey := a.Fingerprint
+	if a.Status == "resolved" {
+		title = "[RESOLVED] " + title
+		dedupKey += ":resolved"
+	}
+
+	return AlertRequest{
+		Priority: severityToPriority(a.Labels["severity"]),
+		Group:    a.Labels["namespace"],
+		Title:    title,
+		Markdown: alertmanagerMarkdown(a),
+		DedupKey: dedupKey,
+		MergeKey: alertmanagerMergeKey(a.Labels),
+	}
+}
+
+func severityToPriority(severity string) Priority {
+	switch severity {
+	case "critical":
+		return PriorityHigh
+	case "warning":
+		return PriorityMed
+	case "info":
+		return PriorityLow
+	default:
+		return PriorityMed
+	}
+}
+
+func alertmanagerMergeKey(labels map[string]string) string {
+	return fmt.Sprintf("%s/%s", labels["alertname"], labels["namespace"])
+}
+
+func alertmanagerMarkdown(a AlertmanagerAlert) string {
+	md := a.Annotations["description"]
+	if md == "" {
+		md = a.Annotations["summary"]
+	}
+	if a.Status == "resolved" && !a.EndsAt.IsZero() {
+		md = fmt.Sprintf("%s\n\nresolved at %s", md, a.EndsAt.Format(time.RFC3339))
+	}
+	if a.GeneratorURL != "" {
+		md = fmt.Sprintf("%s\n\n[source](%s)", md, a.GeneratorURL)
+	}
+	return md
+}
@@ -0,0 +1,53 @@
+package alert
+
+import (
+	"sync"
+
+	"ai-trading-assistant/internal/store"
+)
+
+// Broker fans out every persisted alert to subscribers (the SSE alert
+// stream). Publish never blocks on a slow subscriber: each subscriber
+// channel is buffered, and a full channel simply drops the update rather
+// than stalling alert handling.
+type Broker struct {
+	mu     sync.Mutex
+	nextID int
+	subs   map[int]chan store.AlertRecord
+}
+
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[int]chan store.AlertRecord)}
+}
+
+// Subscribe registers a new subscriber and returns its channel plus an
+// unsubscribe func the caller must invoke when done (typically via defer).
+func (b *Broker) Subscribe() (<-chan store.AlertRecord, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	ch := make(chan store.AlertRecord, 32)
+	b.subs[id] = ch
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if ch, ok := b.subs[id]; ok {
+			delete(b.subs, id)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
+
+func (b *Broker) Publish(rec store.AlertRecord) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.subs {
+		select {
+		case ch <- rec:
+		default:
+		}
+	}
+}
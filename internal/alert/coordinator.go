@@ -0,0 +1,330 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/memberlist"
+)
+
+// ClusterCoordinator lets multiple Service replicas behind a load balancer
+// agree on dedup/merge ownership and divide up the configured rate limit,
+// instead of each replica enforcing DedupWindow/RateLimit independently
+// (which would let N replicas each re-push the same alert and send at N
+// times the configured rate). NewNoopCoordinator is the default, giving
+// today's single-replica deployments the exact behavior they had before
+// this existed.
+type ClusterCoordinator interface {
+	// Owns reports whether this replica is the ring's authoritative owner
+	// for key. Only the owner's local dedup/merge state is treated as
+	// authoritative for that key.
+	Owns(key string) bool
+	// CheckAndMarkDedup asks the cluster for one authoritative dedup
+	// decision on key: the owning replica (forwarding to it over the ring
+	// if this replica isn't the owner) checks-and-marks key against its
+	// own dedup state and the result is returned as deduped. ok is false
+	// when this coordinator can't make that call - a NoopCoordinator, or
+	// a forwarding RPC that errored or timed out - and the caller should
+	// fall back to its own local dedup map instead.
+	CheckAndMarkDedup(ctx context.Context, key string, window time.Duration) (deduped bool, ok bool)
+	// ReplicaCount returns the current ring size, used to scale this
+	// replica's local TokenBucket down to RateLimit.PerMinute/N so the
+	// aggregate send rate across all replicas matches RateLimit.PerMinute.
+	ReplicaCount() int
+	// Join starts gossiping with the ring's seed nodes. A no-op coordinator
+	// returns nil immediately.
+	Join(ctx context.Context) error
+	// Leave gracefully removes this replica from the ring.
+	Leave() error
+}
+
+// NoopCoordinator is the default single-node ClusterCoordinator: this
+// replica always owns every key and the ring always has exactly one member.
+type NoopCoordinator struct{}
+
+func NewNoopCoordinator() *NoopCoordinator { return &NoopCoordinator{} }
+
+func (NoopCoordinator) Owns(string) bool           { return true }
+func (NoopCoordinator) ReplicaCount() int          { return 1 }
+func (NoopCoordinator) Join(context.Context) error { return nil }
+func (NoopCoordinator) Leave() error               { return nil }
+
+// CheckAndMarkDedup always reports ok=false: a single-node deployment has no
+// cluster to forward to, so Service.isDeduped falls back to its own local
+// map, which is already authoritative when there's only one replica.
+func (NoopCoordinator) CheckAndMarkDedup(context.Context, string, time.Duration) (bool, bool) {
+	return false, false
+}
+
+// ClusterConfig configures MemberlistCoordinator's gossip ring.
+type ClusterConfig struct {
+	NodeName string
+	BindAddr string
+	BindPort int
+	Seeds    []string
+}
+
+// dedupRPCKind tags the single byte every MemberlistCoordinator user message
+// starts with, so NotifyMsg can tell a forwarded dedup check apart from its
+// reply without a second round trip.
+type dedupRPCKind byte
+
+const (
+	dedupRPCRequest dedupRPCKind = 1
+	dedupRPCReply   dedupRPCKind = 2
+)
+
+type dedupRequest struct {
+	ID         string
+	FromNode   string
+	Key        string
+	WindowNano int64
+}
+
+type dedupReply struct {
+	ID      string
+	Deduped bool
+}
+
+// MemberlistCoordinator is a memberlist-based gossip ClusterCoordinator,
+// following the consistent-hash-ring pattern Cortex/Loki use for their
+// distributors: every replica sees the same membership list (gossiped by
+// memberlist) and independently computes the same ring, so "who owns key
+// K" agrees across replicas without a central coordinator.
+//
+// Dedup decisions piggyback on the same memberlist connection rather than a
+// separate RPC server: CheckAndMarkDedup on a non-owner replica sends a
+// dedupRequest over memberlist's reliable user-message channel to the
+// owner, which checks-and-marks its own dedup map and sends back a
+// dedupReply; NotifyMsg (the memberlist.Delegate hook) routes both
+// directions. A reply that's lost or never arrives before the caller's
+// context deadline surfaces as ok=false, same as any other forwarding
+// failure, so Service.isDeduped falls back to suppressing locally rather
+// than blocking or passing the alert through unsuppressed.
+type MemberlistCoordinator struct {
+	list     *memberlist.Memberlist
+	nodeName string
+	seeds    []string
+
+	dedupMu sync.Mutex
+	dedup   map[string]time.Time
+
+	reqSeq    uint64
+	pendingMu sync.Mutex
+	pending   map[string]chan bool
+}
+
+func NewMemberlistCoordinator(cfg ClusterConfig) (*MemberlistCoordinator, error) {
+	c := &MemberlistCoordinator{
+		dedup:   make(map[string]time.Time),
+		pending: make(map[string]chan bool),
+	}
+
+	mlConfig := memberlist.DefaultLANConfig()
+	if cfg.NodeName != "" {
+		mlConfig.Name = cfg.NodeName
+	}
+	if cfg.BindAddr != "" {
+		mlConfig.BindAddr = cfg.BindAddr
+	}
+	if cfg.BindPort != 0 {
+		mlConfig.BindPort = cfg.BindPort
+		mlConfig.AdvertisePort = cfg.BindPort
+	}
+	mlConfig.Delegate = c
+
+	list, err := memberlist.Create(mlConfig)
+	if err != nil {
+		return nil, fmt.Errorf("create memberlist: %w", err)
+	}
+	c.list = list
+	c.nodeName = mlConfig.Name
+	c.seeds = cfg.Seeds
+	return c, nil
+}
+
+func (c *MemberlistCoordinator) Join(ctx context.Context) error {
+	if len(c.seeds) == 0 {
+		return nil
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.list.Join(c.seeds)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *MemberlistCoordinator) Leave() error {
+	if err := c.list.Leave(5 * time.Second); err != nil {
+		return err
+	}
+	return c.list.Shutdown()
+}
+
+func (c *MemberlistCoordinator) ReplicaCount() int {
+	return len(c.list.Members())
+}
+
+// Owns hashes key onto the sorted member-name ring and reports whether this
+// node's name is the owner. Every replica sees the same gossiped member
+// list, so every replica computes the same owner for a given key.
+func (c *MemberlistCoordinator) Owns(key string) bool {
+	members := c.list.Members()
+	if len(members) == 0 {
+		return true
+	}
+	owner := c.ownerNode(members, key)
+	return owner == nil || owner.Name == c.nodeName
+}
+
+// CheckAndMarkDedup checks-and-marks key locally if this replica owns it,
+// otherwise forwards the check to the owner and waits for its reply (or
+// ctx's deadline, whichever comes first).
+func (c *MemberlistCoordinator) CheckAndMarkDedup(ctx context.Context, key string, window time.Duration) (bool, bool) {
+	members := c.list.Members()
+	owner := c.ownerNode(members, key)
+	if owner == nil || owner.Name == c.nodeName {
+		return c.checkAndMarkLocal(key, window), true
+	}
+
+	deduped, err := c.forwardDedupCheck(ctx, owner, key, window)
+	if err != nil {
+		return false, false
+	}
+	return deduped, true
+}
+
+func (c *MemberlistCoordinator) ownerNode(members []*memberlist.Node, key string) *memberlist.Node {
+	if len(members) == 0 {
+		return nil
+	}
+	byName := make(map[string]*memberlist.Node, len(members))
+	names := make([]string, len(members))
+	for i, m := range members {
+		names[i] = m.Name
+		byName[m.Name] = m
+	}
+	sort.Strings(names)
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return byName[names[int(h.Sum32())%len(names)]]
+}
+
+func (c *MemberlistCoordinator) checkAndMarkLocal(key string, window time.Duration) bool {
+	now := time.Now()
+	c.dedupMu.Lock()
+	defer c.dedupMu.Unlock()
+	if last, ok := c.dedup[key]; ok && now.Sub(last) <= window {
+		return true
+	}
+	c.dedup[key] = now
+	return false
+}
+
+func (c *MemberlistCoordinator) forwardDedupCheck(ctx context.Context, owner *memberlist.Node, key string, window time.Duration) (bool, error) {
+	id := fmt.Sprintf("%s-%d", c.nodeName, atomic.AddUint64(&c.reqSeq, 1))
+	reply := make(chan bool, 1)
+	c.pendingMu.Lock()
+	c.pending[id] = reply
+	c.pendingMu.Unlock()
+	defer func() {
+		c.pendingMu.Lock()
+		delete(c.pending, id)
+		c.pendingMu.Unlock()
+	}()
+
+	body, err := json.Marshal(dedupRequest{ID: id, FromNode: c.nodeName, Key: key, WindowNano: int64(window)})
+	if err != nil {
+		return false, fmt.Errorf("marshal dedup request: %w", err)
+	}
+	if err := c.list.SendReliable(owner, append([]byte{byte(dedupRPCRequest)}, body...)); err != nil {
+		return false, fmt.Errorf("send dedup request: %w", err)
+	}
+
+	select {
+	case deduped := <-reply:
+		return deduped, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// NodeMeta implements memberlist.Delegate. This coordinator doesn't publish
+// any per-node metadata.
+func (c *MemberlistCoordinator) NodeMeta(limit int) []byte { return nil }
+
+// NotifyMsg implements memberlist.Delegate, routing a forwarded dedup
+// request to checkAndMarkLocal and its reply back to forwardDedupCheck's
+// waiting caller.
+func (c *MemberlistCoordinator) NotifyMsg(buf []byte) {
+	if len(buf) == 0 {
+		return
+	}
+	kind, payload := dedupRPCKind(buf[0]), buf[1:]
+	switch kind {
+	case dedupRPCRequest:
+		var req dedupRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return
+		}
+		deduped := c.checkAndMarkLocal(req.Key, time.Duration(req.WindowNano))
+		c.replyDedup(req, deduped)
+	case dedupRPCReply:
+		var rep dedupReply
+		if err := json.Unmarshal(payload, &rep); err != nil {
+			return
+		}
+		c.pendingMu.Lock()
+		ch, ok := c.pending[rep.ID]
+		c.pendingMu.Unlock()
+		if ok {
+			select {
+			case ch <- rep.Deduped:
+			default:
+			}
+		}
+	}
+}
+
+func (c *MemberlistCoordinator) replyDedup(req dedupRequest, deduped bool) {
+	var from *memberlist.Node
+	for _, m := range c.list.Members() {
+		if m.Name == req.FromNode {
+			from = m
+			break
+		}
+	}
+	if from == nil {
+		return
+	}
+	body, err := json.Marshal(dedupReply{ID: req.ID, Deduped: deduped})
+	if err != nil {
+		return
+	}
+	_ = c.list.SendReliable(from, append([]byte{byte(dedupRPCReply)}, body...))
+}
+
+// GetBroadcasts implements memberlist.Delegate. This coordinator has no
+// gossip broadcasts of its own; dedup requests/replies go over the
+// point-to-point reliable channel instead.
+func (c *MemberlistCoordinator) GetBroadcasts(overhead, limit int) [][]byte { return nil }
+
+// LocalState implements memberlist.Delegate. No state is exchanged on
+// push/pull: dedup state is per-key-owner, not replicated wholesale.
+func (c *MemberlistCoordinator) LocalState(join bool) []byte { return nil }
+
+// MergeRemoteState implements memberlist.Delegate; see LocalState.
+func (c *MemberlistCoordinator) MergeRemoteState(buf []byte, join bool) {}
@@ -0,0 +1,31 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+
+	"ai-trading-assistant/internal/push/dingtalk"
+)
+
+// DingTalkNotifier adapts the shared push/dingtalk client to the Notifier
+// interface so it can be registered like any other channel.
+type DingTalkNotifier struct {
+	client *dingtalk.Client
+}
+
+func NewDingTalkNotifier(client *dingtalk.Client) *DingTalkNotifier {
+	return &DingTalkNotifier{client: client}
+}
+
+func (n *DingTalkNotifier) Name() string { return "dingtalk" }
+
+func (n *DingTalkNotifier) Send(ctx context.Context, title, markdown string, meta map[string]string) (Response, error) {
+	resp, err := n.client.SendMarkdown(ctx, title, markdown)
+	if err != nil {
+		return Response{}, err
+	}
+	if resp.ErrCode != 0 {
+		return Response{Code: resp.ErrCode, Detail: resp.ErrMsg}, fmt.Errorf("dingtalk errcode=%d errmsg=%s", resp.ErrCode, resp.ErrMsg)
+	}
+	return Response{Code: resp.ErrCode, Detail: resp.ErrMsg}, nil
+}
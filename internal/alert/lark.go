@@ -0,0 +1,33 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ai-trading-assistant/internal/push/lark"
+)
+
+// LarkNotifier adapts the shared push/lark.Client to alert.Notifier. Unlike
+// notifier.LarkClient it sends a plain title+markdown card with no severity
+// color or action row, matching this package's existing behavior.
+type LarkNotifier struct {
+	client *lark.Client
+}
+
+func NewLarkNotifier(webhook, secret string, timeout time.Duration) *LarkNotifier {
+	return &LarkNotifier{client: lark.NewClient(webhook, secret, timeout)}
+}
+
+func (n *LarkNotifier) Name() string { return "lark" }
+
+func (n *LarkNotifier) Send(ctx context.Context, title, markdown string, meta map[string]string) (Response, error) {
+	resp, err := n.client.SendCard(ctx, lark.Card{Title: title, Markdown: markdown})
+	if err != nil {
+		return Response{}, err
+	}
+	if resp.Code != 0 {
+		return Response{Code: resp.Code, Detail: resp.Msg}, fmt.Errorf("lark errcode=%d errmsg=%s", resp.Code, resp.Msg)
+	}
+	return Response{Code: resp.Code, Detail: resp.Msg}, nil
+}
@@ -0,0 +1,28 @@
+package alert
+
+import "context"
+
+// Response is a channel-agnostic summary of a single delivery attempt.
+// Code/Detail carry whatever status the channel itself reports (DingTalk's
+// errcode/errmsg, an HTTP status code and body, an SMTP server reply, ...);
+// channels with no such concept (a bare HTTP 2xx webhook) leave them zero.
+type Response struct {
+	Code   int
+	Detail string
+}
+
+// Notifier delivers a single alert to one outbound channel. Service fans a
+// single AlertRequest out to every Notifier configured for its Group,
+// recording a per-channel ChannelResult so a failing Slack webhook never
+// hides a successful DingTalk send (or vice versa).
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, title, markdown string, meta map[string]string) (Response, error)
+}
+
+// ChannelResult records one Notifier's outcome for a single AlertRequest.
+type ChannelResult struct {
+	Channel  string
+	Response Response
+	Err      error
+}
@@ -0,0 +1,371 @@
+package alert
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLowReservationPct    = 0.2
+	defaultHighBorrowCeilingPct = 0.5
+
+	// drrQuantum is the per-call deficit increment for a weight-1
+	// priority (rank 1, i.e. PriorityLow); higher-ranked priorities accrue
+	// deficit proportionally faster, so they clear the >=1 threshold and
+	// get served more often. This approximates classic deficit round robin
+	// without a dedicated dispatch loop: since Handle must decide
+	// synchronously whether to send now or fall back to the digest, the
+	// deficit is evaluated at call time rather than once per scheduler
+	// "round".
+	drrQuantum = 0.34
+
+	statsWindowSize = 256
+)
+
+// Scheduler is alert.Service's priority-aware rate limiter: a global cap
+// (unchanged from the old flat TokenBucket) gates the aggregate send rate,
+// and beneath it each Group gets its own weighted share split into a
+// general pool plus a low-priority reservation, so one noisy group or a
+// burst of low-priority alerts can't starve everyone else.
+type Scheduler struct {
+	mu     sync.Mutex
+	cfg    RateLimitConfig
+	global *TokenBucket
+	groups map[string]*groupBucket
+
+	statsMu sync.Mutex
+	stats   map[string]map[Priority]*priorityStats
+}
+
+// groupBucket is one alert Group's share of the Scheduler's capacity.
+// general serves high/med alerts; low is a dedicated reservation for
+// low-priority alerts that high-priority alerts may borrow from (see
+// borrowFromLow) once general is exhausted. deficits implements the
+// per-priority fairness described on groupBucket.allow.
+type groupBucket struct {
+	mu       sync.Mutex
+	general  *TokenBucket
+	low      *TokenBucket
+	deficits map[Priority]float64
+}
+
+type priorityStats struct {
+	allowed int64
+	dropped int64
+	waits   []float64 // milliseconds, newest last, capped at statsWindowSize
+}
+
+// BucketStats is a point-in-time snapshot of one (group, priority)
+// bucket's load, returned by Scheduler.Stats for the
+// /api/v1/alert/limiter/stats endpoint.
+type BucketStats struct {
+	Group           string  `json:"group"`
+	Priority        string  `json:"priority"`
+	TokensAvailable float64 `json:"tokens_available"`
+	Allowed         int64   `json:"allowed"`
+	Dropped         int64   `json:"dropped"`
+	WaitP50Ms       float64 `json:"wait_p50_ms"`
+	WaitP95Ms       float64 `json:"wait_p95_ms"`
+}
+
+// NewScheduler builds a Scheduler whose global cap is scaled by
+// coordinator's replica count the same way the old flat TokenBucket was
+// (see scaledPerMinute), so a cluster of replicas still shares one
+// aggregate PerMinute/Burst. Per-group buckets are created lazily the
+// first time a group is seen.
+func NewScheduler(cfg RateLimitConfig, coordinator ClusterCoordinator) *Scheduler {
+	if coordinator == nil {
+		coordinator = NewNoopCoordinator()
+	}
+	if cfg.LowReservationPct <= 0 {
+		cfg.LowReservationPct = defaultLowReservationPct
+	}
+	if cfg.HighBorrowCeilingPct <= 0 {
+		cfg.HighBorrowCeilingPct = defaultHighBorrowCeilingPct
+	}
+	return &Scheduler{
+		cfg:    cfg,
+		global: NewTokenBucket(scaledPerMinute(cfg.PerMinute, coordinator), cfg.Burst),
+		groups: make(map[string]*groupBucket),
+		stats:  make(map[string]map[Priority]*priorityStats),
+	}
+}
+
+// Allow reports whether an alert for group at priority may send right now.
+func (sch *Scheduler) Allow(group string, priority Priority) bool {
+	allowed := sch.tryAllow(group, priority)
+	sch.recordOutcome(group, priority, allowed, 0)
+	return allowed
+}
+
+// WaitForToken retries Allow until it succeeds or maxWait elapses,
+// mirroring the old TokenBucket.WaitForToken used for high-priority
+// alerts that would otherwise fall back to the digest.
+func (sch *Scheduler) WaitForToken(group string, priority Priority, maxWait time.Duration) bool {
+	start := time.Now()
+	deadline := start.Add(maxWait)
+	for {
+		if sch.tryAllow(group, priority) {
+			sch.recordOutcome(group, priority, true, time.Since(start))
+			return true
+		}
+		if time.Now().After(deadline) {
+			sch.recordOutcome(group, priority, false, time.Since(start))
+			return false
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// tryAllow checks the global cap first (cheap, and the hard outer bound),
+// then the group's own weighted/DRR decision. Stats are recorded by the
+// caller so WaitForToken can report one accurate wait duration instead of
+// one sample per poll.
+func (sch *Scheduler) tryAllow(group string, priority Priority) bool {
+	if sch.cfg.PerMinute <= 0 {
+		// PerMinute <= 0 means "unlimited" (see NewTokenBucket) — bypass
+		// the DRR deficit gate entirely rather than let its per-call
+		// quantum throttle still apply.
+		return true
+	}
+	if !sch.global.Allow() {
+		return false
+	}
+	gb := sch.getOrCreateGroup(group)
+	return gb.allow(priority, sch.cfg.HighBorrowCeilingPct)
+}
+
+// getOrCreateGroup returns group's bucket, creating it (and rebalancing
+// every existing group's share) if this is the first alert seen for it.
+func (sch *Scheduler) getOrCreateGroup(group string) *groupBucket {
+	sch.mu.Lock()
+	defer sch.mu.Unlock()
+	if gb, ok := sch.groups[group]; ok {
+		return gb
+	}
+	sch.groups[group] = &groupBucket{deficits: make(map[Priority]float64)}
+	sch.rebuildGroupsLocked()
+	return sch.groups[group]
+}
+
+// rebuildGroupsLocked resizes every group's general/low buckets to its
+// weighted share of the global PerMinute/Burst. It runs whenever the set
+// of known groups changes, since each group's fair share depends on the
+// total weight of every other group currently active. Buckets are rebuilt
+// from scratch rather than resized in place — recomputing is cheap and
+// carries no history worth preserving across a rebalance, the same
+// tradeoff SetConfig already makes for the service's other tunables.
+func (sch *Scheduler) rebuildGroupsLocked() {
+	total := 0
+	for name := range sch.groups {
+		total += sch.groupWeight(name)
+	}
+	if total <= 0 {
+		total = 1
+	}
+	for name, gb := range sch.groups {
+		if sch.cfg.PerMinute <= 0 {
+			// PerMinute <= 0 means "unlimited" (see NewTokenBucket) — every
+			// group bucket stays unlimited too, rather than an accidental
+			// 1/minute floor from dividing zero by the group's share.
+			gb.mu.Lock()
+			gb.general = NewTokenBucket(0, 0)
+			gb.low = NewTokenBucket(0, 0)
+			gb.deficits = make(map[Priority]float64)
+			gb.mu.Unlock()
+			continue
+		}
+
+		weight := sch.groupWeight(name)
+		perMinute := divShare(sch.cfg.PerMinute, weight, total)
+		burst := divShare(sch.cfg.Burst, weight, total)
+
+		lowBurst := int(float64(burst) * sch.cfg.LowReservationPct)
+		if lowBurst < 1 {
+			lowBurst = 1
+		}
+		generalBurst := burst - lowBurst
+		if generalBurst < 1 {
+			generalBurst = 1
+		}
+		lowPerMinute := int(float64(perMinute) * sch.cfg.LowReservationPct)
+		if lowPerMinute < 1 {
+			lowPerMinute = 1
+		}
+		generalPerMinute := perMinute - lowPerMinute
+		if generalPerMinute < 1 {
+			generalPerMinute = 1
+		}
+
+		gb.mu.Lock()
+		gb.general = NewTokenBucket(generalPerMinute, generalBurst)
+		gb.low = NewTokenBucket(lowPerMinute, lowBurst)
+		gb.deficits = make(map[Priority]float64)
+		gb.mu.Unlock()
+	}
+}
+
+func divShare(total, weight, totalWeight int) int {
+	share := total * weight / totalWeight
+	if share < 1 {
+		share = 1
+	}
+	return share
+}
+
+func (sch *Scheduler) groupWeight(group string) int {
+	if w, ok := sch.cfg.GroupWeights[group]; ok && w > 0 {
+		return w
+	}
+	return 1
+}
+
+// allow draws a token for priority, applying the group's deficit-round-robin
+// check only when the bucket priority draws from is actually contended
+// (fewer than one token sitting available). With tokens to spare there's no
+// contention for DRR to arbitrate, so the draw goes through uncontested;
+// once the bucket runs dry, only a priority whose deficit has cleared 1
+// (accrued proportional to its rank — high > med > low) gets to drain it as
+// tokens trickle back in, so one priority's burst can't starve the others.
+// Low priority draws from the reservation; high and med draw from the
+// general pool, with high allowed to borrow from the reservation (see
+// borrowFromLow) once general is dry.
+func (gb *groupBucket) allow(priority Priority, highBorrowCeilingPct float64) bool {
+	gb.mu.Lock()
+	defer gb.mu.Unlock()
+
+	if gb.contendedBucket(priority).tokensSnapshot() >= 1 {
+		return gb.draw(priority, highBorrowCeilingPct)
+	}
+
+	gb.deficits[priority] += drrQuantum * float64(rank(priority))
+	if gb.deficits[priority] < 1 {
+		return false
+	}
+	if !gb.draw(priority, highBorrowCeilingPct) {
+		return false
+	}
+	gb.deficits[priority]--
+	return true
+}
+
+// contendedBucket is the bucket priority draws from absent borrowing, used
+// only to peek whether it's currently contended.
+func (gb *groupBucket) contendedBucket(priority Priority) *TokenBucket {
+	if priority == PriorityLow {
+		return gb.low
+	}
+	return gb.general
+}
+
+func (gb *groupBucket) draw(priority Priority, highBorrowCeilingPct float64) bool {
+	switch priority {
+	case PriorityLow:
+		return gb.low.Allow()
+	default:
+		if gb.general.Allow() {
+			return true
+		}
+		return priority == PriorityHigh && gb.borrowFromLow(highBorrowCeilingPct)
+	}
+}
+
+// borrowFromLow lets a high-priority alert draw a token from the group's
+// low-priority reservation once its own general pool is exhausted, but
+// never past highBorrowCeilingPct of that reservation's burst — leaving at
+// least (1-ceiling) of the reservation for actual low-priority alerts.
+func (gb *groupBucket) borrowFromLow(highBorrowCeilingPct float64) bool {
+	gb.low.mu.Lock()
+	defer gb.low.mu.Unlock()
+	gb.low.refillLocked()
+	floor := gb.low.burst * (1 - highBorrowCeilingPct)
+	if gb.low.tokens-1 < floor {
+		return false
+	}
+	gb.low.tokens--
+	return true
+}
+
+func (sch *Scheduler) recordOutcome(group string, priority Priority, allowed bool, wait time.Duration) {
+	sch.statsMu.Lock()
+	defer sch.statsMu.Unlock()
+	byPriority, ok := sch.stats[group]
+	if !ok {
+		byPriority = make(map[Priority]*priorityStats)
+		sch.stats[group] = byPriority
+	}
+	ps, ok := byPriority[priority]
+	if !ok {
+		ps = &priorityStats{}
+		byPriority[priority] = ps
+	}
+	if allowed {
+		ps.allowed++
+	} else {
+		ps.dropped++
+	}
+	ps.waits = append(ps.waits, float64(wait.Milliseconds()))
+	if len(ps.waits) > statsWindowSize {
+		ps.waits = ps.waits[len(ps.waits)-statsWindowSize:]
+	}
+}
+
+// Stats returns a snapshot of every (group, priority) bucket seen so far,
+// sorted by group name, for the /api/v1/alert/limiter/stats endpoint.
+func (sch *Scheduler) Stats() []BucketStats {
+	sch.mu.Lock()
+	names := make([]string, 0, len(sch.groups))
+	snapshot := make(map[string]*groupBucket, len(sch.groups))
+	for name, gb := range sch.groups {
+		names = append(names, name)
+		snapshot[name] = gb
+	}
+	sch.mu.Unlock()
+	sort.Strings(names)
+
+	sch.statsMu.Lock()
+	defer sch.statsMu.Unlock()
+
+	priorities := []Priority{PriorityHigh, PriorityMed, PriorityLow}
+	out := make([]BucketStats, 0, len(names)*len(priorities))
+	for _, name := range names {
+		gb := snapshot[name]
+		gb.mu.Lock()
+		generalTokens := gb.general.tokensSnapshot()
+		lowTokens := gb.low.tokensSnapshot()
+		gb.mu.Unlock()
+
+		for _, p := range priorities {
+			tokens := generalTokens
+			if p == PriorityLow {
+				tokens = lowTokens
+			}
+			bs := BucketStats{Group: name, Priority: string(p), TokensAvailable: tokens}
+			if ps := sch.stats[name][p]; ps != nil {
+				bs.Allowed = ps.allowed
+				bs.Dropped = ps.dropped
+				bs.WaitP50Ms = quantile(ps.waits, 0.50)
+				bs.WaitP95Ms = quantile(ps.waits, 0.95)
+			}
+			out = append(out, bs)
+		}
+	}
+	return out
+}
+
+func quantile(samples []float64, q float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	idx := int(q * float64(len(sorted)-1))
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
@@ -2,6 +2,7 @@ package alert
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"sort"
@@ -9,10 +10,12 @@ import (
 	"sync"
 	"time"
 
-	"ai-trading-assistant/internal/push/dingtalk"
+	"ai-trading-assistant/internal/metrics"
 	"ai-trading-assistant/internal/store"
 )
 
+const walOrphanAge = 24 * time.Hour
+
 type Priority string
 
 const (
@@ -43,8 +46,13 @@ const (
 type Result struct {
 	Status          Status
 	Error           error
+	Channels        []ChannelResult
 	DingTalkErrCode int
 	DingTalkErrMsg  string
+	// SuppressReason explains a StatusSuppressed result that came from a
+	// matching Silence, formatted as "silence:<id>". Empty for every other
+	// suppression cause (a Silent request, or a dedup hit).
+	SuppressReason string
 }
 
 type Config struct {
@@ -52,18 +60,46 @@ type Config struct {
 	DedupWindow       time.Duration
 	MergeWindow       time.Duration
 	LowDigestInterval time.Duration
+
+	// Routing maps an AlertRequest's Group to the channel names that should
+	// receive it. A group with no entry falls back to DefaultChannels.
+	Routing         map[string][]string
+	DefaultChannels []string
 }
 
 type RateLimitConfig struct {
 	PerMinute int
 	Burst     int
+
+	// GroupWeights gives each alert Group a share of PerMinute/Burst
+	// relative to other groups; a group with no entry gets weight 1.
+	GroupWeights map[string]int
+
+	// LowReservationPct is the fraction of a group's capacity set aside
+	// for PriorityLow draws on the Scheduler; high/med draw from the rest.
+	// handleSendOrDigest queues low-priority alerts to the digest without
+	// drawing from the Scheduler, so in Service only high-priority
+	// borrowing (HighBorrowCeilingPct) uses the reservation. Zero is
+	// treated as the Scheduler's default (0.2).
+	LowReservationPct float64
+
+	// HighBorrowCeilingPct caps how much of the low-priority reservation a
+	// high-priority alert may borrow once a group's general pool is
+	// exhausted, as a fraction of that reservation. Zero is treated as the
+	// Scheduler's default (0.5).
+	HighBorrowCeilingPct float64
 }
 
 type Service struct {
-	dt      *dingtalk.Client
-	cfg     Config
-	limiter *TokenBucket
-	store   *store.Store
+	notifiers   map[string]Notifier
+	coordinator ClusterCoordinator
+	store       *store.Store
+	broker      *Broker
+
+	cfgMu        sync.RWMutex
+	cfg          Config
+	scheduler    *Scheduler
+	digestTicker *time.Ticker
 
 	dedupMu sync.Mutex
 	dedup   map[string]time.Time
@@ -74,31 +110,245 @@ type Service struct {
 	digestMu sync.Mutex
 	digest   map[string][]AlertRequest
 
+	silenceMu sync.RWMutex
+	silences  []Silence
+
 	stopCh chan struct{}
 }
 
 type mergeState struct {
-	alerts []AlertRequest
-	timer  *time.Timer
+	alerts  []AlertRequest
+	timer   *time.Timer
+	flushAt time.Time
 }
 
-func NewService(dt *dingtalk.Client, st *store.Store, cfg Config) *Service {
+// NewService wires a Service to its notifier registry, cluster coordinator,
+// and store. coordinator may be nil, in which case a NewNoopCoordinator is
+// used and the service behaves exactly as it did before ClusterCoordinator
+// existed (single replica, no rate-limit scaling).
+func NewService(notifiers map[string]Notifier, coordinator ClusterCoordinator, st *store.Store, cfg Config) *Service {
+	if coordinator == nil {
+		coordinator = NewNoopCoordinator()
+	}
 	s := &Service{
-		dt:      dt,
-		cfg:     cfg,
-		limiter: NewTokenBucket(cfg.RateLimit.PerMinute, cfg.RateLimit.Burst),
-		store:   st,
-		dedup:   make(map[string]time.Time),
-		merge:   make(map[string]*mergeState),
-		digest:  make(map[string][]AlertRequest),
-		stopCh:  make(chan struct{}),
+		notifiers:   notifiers,
+		coordinator: coordinator,
+		cfg:         cfg,
+		scheduler:   NewScheduler(cfg.RateLimit, coordinator),
+		store:       st,
+		broker:      NewBroker(),
+		dedup:       make(map[string]time.Time),
+		merge:       make(map[string]*mergeState),
+		digest:      make(map[string][]AlertRequest),
+		stopCh:      make(chan struct{}),
 	}
 	if cfg.LowDigestInterval > 0 {
+		s.digestTicker = time.NewTicker(cfg.LowDigestInterval)
 		go s.runDigestLoop()
 	}
+	if _, ok := coordinator.(*NoopCoordinator); !ok {
+		go s.runClusterWatch()
+	}
+	s.replayWAL()
+	if s.store != nil {
+		go s.runWALMaintenance()
+		s.reloadSilences(false)
+		go s.runSilenceSweep()
+	}
 	return s
 }
 
+// replayWAL reconstructs pending merge batches and digest groups from rows
+// a prior process persisted but never flushed before it stopped, so a
+// restart between enqueue and flush doesn't lose the alert. A merge row's
+// timer is rescheduled for whatever remains of its original window (firing
+// immediately if that window has already passed); digest rows are simply
+// re-appended since the digest ticker will pick them up on its next tick.
+func (s *Service) replayWAL() {
+	if s.store == nil {
+		return
+	}
+	rows, err := s.store.QueryAlertWAL()
+	if err != nil {
+		log.Printf("replay alert wal: %v", err)
+		return
+	}
+	now := time.Now()
+	for _, row := range rows {
+		var req AlertRequest
+		if err := json.Unmarshal([]byte(row.ReqJSON), &req); err != nil {
+			log.Printf("replay alert wal: decode row %d: %v", row.ID, err)
+			continue
+		}
+		switch row.Kind {
+		case "merge":
+			s.replayMergeRow(row, req, now)
+		case "digest":
+			s.digestMu.Lock()
+			s.digest[row.GroupName] = append(s.digest[row.GroupName], req)
+			s.digestMu.Unlock()
+		default:
+			log.Printf("replay alert wal: unknown kind %q for row %d", row.Kind, row.ID)
+		}
+	}
+}
+
+func (s *Service) replayMergeRow(row store.AlertWALRecord, req AlertRequest, now time.Time) {
+	s.mergeMu.Lock()
+	defer s.mergeMu.Unlock()
+	state, ok := s.merge[row.Key]
+	if !ok {
+		flushAt := time.Unix(row.FlushAt, 0)
+		remaining := flushAt.Sub(now)
+		if remaining < 0 {
+			remaining = 0
+		}
+		state = &mergeState{flushAt: flushAt}
+		key := row.Key
+		state.timer = time.AfterFunc(remaining, func() {
+			s.flushMerge(key)
+		})
+		s.merge[row.Key] = state
+	}
+	state.alerts = append(state.alerts, req)
+}
+
+// runWALMaintenance periodically reclaims WAL rows older than walOrphanAge
+// that were never flushed, guarding against rows left behind by a bug or a
+// config change that shortened a merge/digest window after the row was
+// written.
+func (s *Service) runWALMaintenance() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			cutoff := time.Now().Add(-walOrphanAge).Unix()
+			n, err := s.store.ReclaimOrphanedAlertWAL(cutoff)
+			if err != nil {
+				log.Printf("reclaim orphaned alert wal: %v", err)
+			} else if n > 0 {
+				log.Printf("reclaimed %d orphaned alert wal rows", n)
+			}
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// scaledPerMinute divides perMinute by the ring's current replica count, so
+// the aggregate send rate across every replica matches RateLimit.PerMinute
+// regardless of how many replicas are running.
+func scaledPerMinute(perMinute int, coordinator ClusterCoordinator) int {
+	n := coordinator.ReplicaCount()
+	if n <= 1 {
+		return perMinute
+	}
+	scaled := perMinute / n
+	if scaled < 1 {
+		scaled = 1
+	}
+	return scaled
+}
+
+// runClusterWatch re-derives the token bucket whenever the ring's replica
+// count changes, so a replica joining or leaving reallocates the shared
+// rate limit without requiring a config reload.
+func (s *Service) runClusterWatch() {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+	last := s.coordinator.ReplicaCount()
+	for {
+		select {
+		case <-ticker.C:
+			n := s.coordinator.ReplicaCount()
+			if n == last {
+				continue
+			}
+			last = n
+			cfg := s.getCfg()
+			s.cfgMu.Lock()
+			s.scheduler = NewScheduler(cfg.RateLimit, s.coordinator)
+			s.cfgMu.Unlock()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// SetConfig swaps the service's tunables at runtime — rate limit, dedup/merge
+// windows, digest interval — so a config.Manager reload takes effect without
+// restarting the process. Per-key dedup/merge/digest state is left untouched;
+// only the thresholds governing it change. The scheduler is rebuilt from
+// scratch rather than adjusted in place, since recomputing it is cheap and it
+// carries no history worth preserving across a reload (its Stats() counters
+// reset too). If a digest loop is already running its ticker is reset to the
+// new interval; a reload that enables digesting for the first time (interval
+// 0 -> positive) starts one.
+func (s *Service) SetConfig(cfg Config) {
+	s.cfgMu.Lock()
+	defer s.cfgMu.Unlock()
+	s.cfg = cfg
+	s.scheduler = NewScheduler(cfg.RateLimit, s.coordinator)
+	switch {
+	case s.digestTicker != nil && cfg.LowDigestInterval > 0:
+		s.digestTicker.Reset(cfg.LowDigestInterval)
+	case s.digestTicker == nil && cfg.LowDigestInterval > 0:
+		s.digestTicker = time.NewTicker(cfg.LowDigestInterval)
+		go s.runDigestLoop()
+	}
+}
+
+// getCfg returns the service's current Config under cfgMu, so a concurrent
+// SetConfig reload never races with a request reading thresholds mid-decision.
+func (s *Service) getCfg() Config {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	return s.cfg
+}
+
+func (s *Service) getScheduler() *Scheduler {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	return s.scheduler
+}
+
+// LimiterStats returns a snapshot of every (group, priority) bucket the
+// scheduler is tracking, for the /api/v1/alert/limiter/stats endpoint.
+func (s *Service) LimiterStats() []BucketStats {
+	sched := s.getScheduler()
+	if sched == nil {
+		return nil
+	}
+	return sched.Stats()
+}
+
+// channelsFor resolves the Notifiers a group's alerts should fan out to:
+// its Routing entry if one exists, otherwise DefaultChannels. Unknown
+// channel names (misconfigured or not wired into s.notifiers) are skipped
+// rather than erroring, since a bad name shouldn't drop the whole alert.
+func (s *Service) channelsFor(group string) []Notifier {
+	cfg := s.getCfg()
+	names := cfg.Routing[group]
+	if len(names) == 0 {
+		names = cfg.DefaultChannels
+	}
+	channels := make([]Notifier, 0, len(names))
+	for _, name := range names {
+		if n, ok := s.notifiers[name]; ok {
+			channels = append(channels, n)
+		}
+	}
+	return channels
+}
+
+// Broker returns the pub/sub layer that every persisted alert is fanned out
+// to, so API-layer streaming handlers (e.g. the SSE alert stream) can
+// subscribe without this package knowing about transports.
+func (s *Service) Broker() *Broker {
+	return s.broker
+}
+
 func (s *Service) Handle(ctx context.Context, req AlertRequest) Result {
 	req = normalize(req)
 	if req.Silent {
@@ -107,13 +357,20 @@ func (s *Service) Handle(ctx context.Context, req AlertRequest) Result {
 		return res
 	}
 
+	if sil, ok := s.matchSilence(req); ok {
+		res := Result{Status: StatusSuppressed, SuppressReason: fmt.Sprintf("silence:%d", sil.ID)}
+		s.recordAlert(req, res, "")
+		s.recordSilenceFired(sil, req)
+		return res
+	}
+
 	if s.isDeduped(req) {
 		res := Result{Status: StatusSuppressed}
 		s.recordAlert(req, res, "")
 		return res
 	}
 
-	if req.MergeKey != "" && s.cfg.MergeWindow > 0 {
+	if req.MergeKey != "" && s.getCfg().MergeWindow > 0 {
 		s.enqueueMerge(req)
 		res := Result{Status: StatusMergedPending}
 		s.recordAlert(req, res, "")
@@ -131,13 +388,14 @@ func (s *Service) handleSendOrDigest(ctx context.Context, req AlertRequest) (Res
 		return Result{Status: StatusQueuedDigest}, ""
 	}
 
-	if s.limiter == nil || s.limiter.Allow() {
-		return s.sendNow(ctx, req), req.Markdown
+	sched := s.getScheduler()
+	if sched == nil || sched.Allow(req.Group, req.Priority) {
+		return s.fanOutSend(ctx, req), req.Markdown
 	}
 
 	if req.Priority == PriorityHigh {
-		if s.limiter.WaitForToken(2 * time.Second) {
-			return s.sendNow(ctx, req), req.Markdown
+		if sched.WaitForToken(req.Group, req.Priority, 2*time.Second) {
+			return s.fanOutSend(ctx, req), req.Markdown
 		}
 		s.addDigest(req)
 		return Result{Status: StatusQueuedDigest}, ""
@@ -148,34 +406,83 @@ func (s *Service) handleSendOrDigest(ctx context.Context, req AlertRequest) (Res
 	return Result{Status: StatusQueuedDigest}, ""
 }
 
-func (s *Service) sendNow(ctx context.Context, req AlertRequest) Result {
-	if s.dt == nil {
-		return Result{Status: StatusSent, Error: fmt.Errorf("dingtalk client not configured")}
+// fanOutSend delivers req to every Notifier configured for its Group
+// concurrently, mirroring notifier.Service.Notify's fan-out so one slow or
+// failing channel never delays the others. The first channel error becomes
+// Result.Error; DingTalkErrCode/DingTalkErrMsg are populated only when a
+// "dingtalk" channel result is present, to keep that legacy column filled.
+func (s *Service) fanOutSend(ctx context.Context, req AlertRequest) Result {
+	channels := s.channelsFor(req.Group)
+	if len(channels) == 0 {
+		return Result{Status: StatusSent, Error: fmt.Errorf("no notifier channels configured for group %q", req.Group)}
 	}
-	resp, err := s.dt.SendMarkdown(ctx, req.Title, req.Markdown)
-	if err != nil {
-		return Result{Status: StatusSent, Error: err}
+
+	results := make([]ChannelResult, len(channels))
+	var wg sync.WaitGroup
+	for i, n := range channels {
+		wg.Add(1)
+		go func(i int, n Notifier) {
+			defer wg.Done()
+			results[i] = sendViaChannel(ctx, n, req.Title, req.Markdown, map[string]string{"severity": string(req.Priority)})
+		}(i, n)
 	}
-	if resp.ErrCode != 0 {
-		return Result{
-			Status:          StatusSent,
-			DingTalkErrCode: resp.ErrCode,
-			DingTalkErrMsg:  resp.ErrMsg,
-			Error:           fmt.Errorf("dingtalk errcode=%d errmsg=%s", resp.ErrCode, resp.ErrMsg),
+	wg.Wait()
+
+	res := Result{Status: StatusSent, Channels: results}
+	for _, cr := range results {
+		if cr.Channel == "dingtalk" {
+			res.DingTalkErrCode = cr.Response.Code
+			res.DingTalkErrMsg = cr.Response.Detail
+		}
+		if cr.Err != nil && res.Error == nil {
+			res.Error = cr.Err
 		}
 	}
-	return Result{Status: StatusSent, DingTalkErrCode: resp.ErrCode, DingTalkErrMsg: resp.ErrMsg}
+	return res
 }
 
+// sendViaChannel runs a single Notifier and records how long it took,
+// reusing the same histogram internal/notifier's sendWithRetry reports to.
+func sendViaChannel(ctx context.Context, n Notifier, title, markdown string, meta map[string]string) ChannelResult {
+	start := time.Now()
+	resp, err := n.Send(ctx, title, markdown, meta)
+	metrics.NotifierSendDuration.WithLabelValues(n.Name()).Observe(time.Since(start).Seconds())
+	if err != nil {
+		log.Printf("alert notifier %s send error: %v", n.Name(), err)
+	}
+	return ChannelResult{Channel: n.Name(), Response: resp, Err: err}
+}
+
+// dedupRPCTimeout bounds how long isDeduped waits on a non-owner replica's
+// forwarded CheckAndMarkDedup before falling back to this replica's own
+// local map - long enough for a healthy gossip round trip, short enough
+// that a stalled owner doesn't hold up alert delivery.
+const dedupRPCTimeout = 500 * time.Millisecond
+
+// isDeduped enforces DedupWindow. When this replica owns req.DedupKey (per
+// ClusterCoordinator.Owns) the coordinator checks-and-marks its own
+// authoritative map; otherwise it forwards the check to the owner so the
+// whole cluster shares one dedup decision for the key. If the coordinator
+// can't make that call - a NoopCoordinator, or a forwarding RPC that errored
+// or timed out - isDeduped falls back to this replica's own local map,
+// which is strictly better than passing every alert through unsuppressed.
 func (s *Service) isDeduped(req AlertRequest) bool {
-	if req.DedupKey == "" || s.cfg.DedupWindow <= 0 {
+	dedupWindow := s.getCfg().DedupWindow
+	if req.DedupKey == "" || dedupWindow <= 0 {
 		return false
 	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dedupRPCTimeout)
+	defer cancel()
+	if deduped, ok := s.coordinator.CheckAndMarkDedup(ctx, req.DedupKey, dedupWindow); ok {
+		return deduped
+	}
+
 	now := time.Now()
 	s.dedupMu.Lock()
 	defer s.dedupMu.Unlock()
 	if last, ok := s.dedup[req.DedupKey]; ok {
-		if now.Sub(last) <= s.cfg.DedupWindow {
+		if now.Sub(last) <= dedupWindow {
 			return true
 		}
 	}
@@ -188,13 +495,14 @@ func (s *Service) enqueueMerge(req AlertRequest) {
 	defer s.mergeMu.Unlock()
 	state, ok := s.merge[req.MergeKey]
 	if !ok {
-		state = &mergeState{}
+		state = &mergeState{flushAt: time.Now().Add(s.getCfg().MergeWindow)}
 		s.merge[req.MergeKey] = state
-		state.timer = time.AfterFunc(s.cfg.MergeWindow, func() {
+		state.timer = time.AfterFunc(s.getCfg().MergeWindow, func() {
 			s.flushMerge(req.MergeKey)
 		})
 	}
 	state.alerts = append(state.alerts, req)
+	s.persistWAL("merge", req.MergeKey, req.Group, req, state.flushAt)
 }
 
 func (s *Service) flushMerge(key string) {
@@ -210,29 +518,291 @@ func (s *Service) flushMerge(key string) {
 
 	merged := buildMerged(state.alerts)
 	if merged.Silent {
+		s.clearWAL("merge", key)
 		return
 	}
 
+	// Handle runs the send and its recordAlert store write synchronously
+	// (see fanOutSend's wg.Wait before Handle returns), so the WAL row is
+	// only cleared once the alert has actually been persisted/delivered -
+	// a crash before that point leaves it in place for replayWAL to retry,
+	// rather than silently dropping it.
 	_ = s.Handle(context.Background(), merged)
+	s.clearWAL("merge", key)
+}
+
+// persistWAL records req in the write-ahead log so it survives a restart
+// before its merge batch or digest group flushes. Failures are logged, not
+// surfaced, since the in-memory state (which still drives normal delivery)
+// has already been updated by the caller - losing durability on a store
+// error shouldn't also drop the alert that's otherwise being handled fine.
+func (s *Service) persistWAL(kind, key, group string, req AlertRequest, flushAt time.Time) {
+	if s.store == nil {
+		return
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		log.Printf("persist alert wal: encode request: %v", err)
+		return
+	}
+	rec := store.AlertWALRecord{
+		Kind:       kind,
+		Key:        key,
+		GroupName:  group,
+		ReqJSON:    string(body),
+		EnqueuedAt: time.Now().Unix(),
+		FlushAt:    flushAt.Unix(),
+	}
+	if err := s.store.InsertAlertWAL(rec); err != nil {
+		log.Printf("persist alert wal: %v", err)
+	}
+}
+
+func (s *Service) clearWAL(kind, key string) {
+	if s.store == nil {
+		return
+	}
+	if err := s.store.DeleteAlertWAL(kind, key); err != nil {
+		log.Printf("clear alert wal: %v", err)
+	}
+}
+
+// CancelMerge discards any pending merge state for key without sending it.
+// Used when an upstream source (e.g. an Alertmanager resolved notification)
+// reports the underlying condition is already over before MergeWindow
+// elapses, so the merged batch never fires after the fact.
+func (s *Service) CancelMerge(key string) bool {
+	if key == "" {
+		return false
+	}
+	s.mergeMu.Lock()
+	defer s.mergeMu.Unlock()
+	state, ok := s.merge[key]
+	if !ok {
+		return false
+	}
+	state.timer.Stop()
+	delete(s.merge, key)
+	s.clearWAL("merge", key)
+	return true
+}
+
+// matchSilence returns the first active, cached silence whose matchers all
+// pass against req. Silences are checked in the order store.QueryActive
+// Silences returned them (newest first), so the most recently created
+// silence wins when more than one matches.
+func (s *Service) matchSilence(req AlertRequest) (Silence, bool) {
+	s.silenceMu.RLock()
+	defer s.silenceMu.RUnlock()
+	now := time.Now()
+	for _, sil := range s.silences {
+		if sil.active(now) && sil.matchesAll(req) {
+			return sil, true
+		}
+	}
+	return Silence{}, false
+}
+
+// recordSilenceFired emits an audit event recording that sil suppressed
+// req, so the UI can show how many alerts a silence absorbed.
+func (s *Service) recordSilenceFired(sil Silence, req AlertRequest) {
+	if s.store == nil {
+		return
+	}
+	evt := store.EventRecord{
+		TS:        time.Now().Unix(),
+		Type:      "silence_fired",
+		Severity:  string(req.Priority),
+		GroupName: req.Group,
+		Title:     req.Title,
+		MergeKey:  fmt.Sprintf("silence:%d", sil.ID),
+	}
+	if err := s.store.InsertEvent(evt); err != nil {
+		log.Printf("insert silence fired event error: %v", err)
+	}
+}
+
+// CreateSilence persists a new silence and adds it to the active cache if
+// its window has already started.
+func (s *Service) CreateSilence(sil Silence) (Silence, error) {
+	if s.store == nil {
+		return Silence{}, fmt.Errorf("store not configured")
+	}
+	body, err := json.Marshal(sil.Matchers)
+	if err != nil {
+		return Silence{}, fmt.Errorf("encode matchers: %w", err)
+	}
+	rec := store.SilenceRecord{
+		MatchersJSON: string(body),
+		StartsAt:     sil.StartsAt.Unix(),
+		EndsAt:       sil.EndsAt.Unix(),
+		CreatedBy:    sil.CreatedBy,
+		Comment:      sil.Comment,
+	}
+	id, err := s.store.InsertSilence(rec)
+	if err != nil {
+		return Silence{}, err
+	}
+	sil.ID = id
+	s.reloadSilences(false)
+	return sil, nil
+}
+
+// ListSilences returns every silence, active or expired, for the CRUD
+// listing endpoint.
+func (s *Service) ListSilences() ([]Silence, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("store not configured")
+	}
+	recs, err := s.store.QuerySilences()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Silence, 0, len(recs))
+	for _, rec := range recs {
+		sil, err := silenceFromRecord(rec)
+		if err != nil {
+			log.Printf("decode silence %d: %v", rec.ID, err)
+			continue
+		}
+		out = append(out, sil)
+	}
+	return out, nil
+}
+
+// DeleteSilence removes a silence, e.g. when a maintenance window ends
+// early, and drops it from the active cache immediately.
+func (s *Service) DeleteSilence(id int64) (bool, error) {
+	if s.store == nil {
+		return false, fmt.Errorf("store not configured")
+	}
+	ok, err := s.store.DeleteSilence(id)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		s.reloadSilences(false)
+	}
+	return ok, nil
+}
+
+// reloadSilences refreshes the active-silence cache from the store. When
+// emitExpiry is true (the sweeper's periodic call), it also diffs against
+// the previous cache to find silences that just aged out of the active
+// window and emits a "silence_expired" audit event for each still-existing
+// one — a silence that disappeared because it was deleted is not an
+// expiry and gets no event.
+func (s *Service) reloadSilences(emitExpiry bool) {
+	if s.store == nil {
+		return
+	}
+	now := time.Now()
+	recs, err := s.store.QueryActiveSilences(now.Unix())
+	if err != nil {
+		log.Printf("reload silences: %v", err)
+		return
+	}
+	next := make([]Silence, 0, len(recs))
+	nextIDs := make(map[int64]bool, len(recs))
+	for _, rec := range recs {
+		sil, err := silenceFromRecord(rec)
+		if err != nil {
+			log.Printf("decode silence %d: %v", rec.ID, err)
+			continue
+		}
+		next = append(next, sil)
+		nextIDs[sil.ID] = true
+	}
+
+	s.silenceMu.Lock()
+	prev := s.silences
+	s.silences = next
+	s.silenceMu.Unlock()
+
+	if !emitExpiry {
+		return
+	}
+	for _, sil := range prev {
+		if nextIDs[sil.ID] {
+			continue
+		}
+		if _, ok, err := s.store.GetSilence(sil.ID); err != nil {
+			log.Printf("check silence %d: %v", sil.ID, err)
+		} else if ok {
+			s.recordSilenceExpired(sil)
+		}
+	}
+}
+
+func (s *Service) recordSilenceExpired(sil Silence) {
+	evt := store.EventRecord{
+		TS:       time.Now().Unix(),
+		Type:     "silence_expired",
+		MergeKey: fmt.Sprintf("silence:%d", sil.ID),
+	}
+	if err := s.store.InsertEvent(evt); err != nil {
+		log.Printf("insert silence expired event error: %v", err)
+	}
+}
+
+// runSilenceSweep periodically reloads the active-silence cache, which
+// both picks up newly created silences and detects ones that just expired.
+func (s *Service) runSilenceSweep() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.reloadSilences(true)
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func silenceFromRecord(rec store.SilenceRecord) (Silence, error) {
+	var matchers []Matcher
+	if err := json.Unmarshal([]byte(rec.MatchersJSON), &matchers); err != nil {
+		return Silence{}, fmt.Errorf("decode matchers: %w", err)
+	}
+	createdAt, _ := time.Parse(time.RFC3339, rec.CreatedAt)
+	return Silence{
+		ID:        rec.ID,
+		Matchers:  matchers,
+		StartsAt:  time.Unix(rec.StartsAt, 0),
+		EndsAt:    time.Unix(rec.EndsAt, 0),
+		CreatedBy: rec.CreatedBy,
+		Comment:   rec.Comment,
+		CreatedAt: createdAt,
+	}, nil
 }
 
 func (s *Service) addDigest(req AlertRequest) {
-	if s.cfg.LowDigestInterval <= 0 {
+	cfg := s.getCfg()
+	if cfg.LowDigestInterval <= 0 {
 		return
 	}
 	s.digestMu.Lock()
-	defer s.digestMu.Unlock()
 	group := req.Group
 	if group == "" {
 		group = "default"
 	}
 	s.digest[group] = append(s.digest[group], req)
+	s.digestMu.Unlock()
+	s.persistWAL("digest", group, group, req, time.Now().Add(cfg.LowDigestInterval))
 }
 
+// runDigestLoop drains s.digestTicker until stopCh closes. SetConfig resets
+// the same ticker in place when LowDigestInterval changes, so this loop never
+// needs to be restarted on a reload.
 func (s *Service) runDigestLoop() {
-	ticker := time.NewTicker(s.cfg.LowDigestInterval)
-	defer ticker.Stop()
 	for {
+		s.cfgMu.RLock()
+		ticker := s.digestTicker
+		s.cfgMu.RUnlock()
+		if ticker == nil {
+			return
+		}
 		select {
 		case <-ticker.C:
 			s.flushDigest()
@@ -242,49 +812,98 @@ func (s *Service) runDigestLoop() {
 	}
 }
 
+// flushDigest sends the combined digest through DefaultChannels rather than
+// per-group routing: one digest message spans every group's low-priority
+// alerts, so there's no single Group to route on.
 func (s *Service) flushDigest() {
 	groups := s.swapDigest()
 	if len(groups) == 0 {
 		return
 	}
 
-	if s.dt == nil {
-		log.Printf("digest send skipped: dingtalk client not configured")
+	channels := s.defaultChannels()
+	if len(channels) == 0 {
+		log.Printf("digest send skipped: no default notifier channels configured")
 		return
 	}
 
 	title := "Low Alert Digest"
 	markdown := buildDigestMarkdown(groups)
-	resp, err := s.dt.SendMarkdown(context.Background(), title, markdown)
-	if err != nil {
-		log.Printf("digest send error: %v", err)
-		return
+	severity := maxDigestPriority(groups)
+	var wg sync.WaitGroup
+	for _, n := range channels {
+		wg.Add(1)
+		go func(n Notifier) {
+			defer wg.Done()
+			cr := sendViaChannel(context.Background(), n, title, markdown, map[string]string{"severity": string(severity)})
+			if cr.Err != nil {
+				log.Printf("digest send error via %s: %v", cr.Channel, cr.Err)
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	// Only clear each group's WAL row once the digest send above has
+	// actually been attempted, so a crash mid-send still leaves a WAL
+	// trace for replayWAL to retry instead of silently dropping it.
+	for group := range groups {
+		s.clearWAL("digest", group)
 	}
-	if resp.ErrCode != 0 {
-		log.Printf("digest dingtalk error: errcode=%d errmsg=%s", resp.ErrCode, resp.ErrMsg)
+}
+
+func (s *Service) defaultChannels() []Notifier {
+	names := s.getCfg().DefaultChannels
+	channels := make([]Notifier, 0, len(names))
+	for _, name := range names {
+		if n, ok := s.notifiers[name]; ok {
+			channels = append(channels, n)
+		}
 	}
+	return channels
 }
 
+// recordAlert persists one AlertRecord per channel-delivery attempt, so a
+// Handle call that fanned out to three channels leaves three rows
+// distinguished by Channel. Paths with no send attempt (suppressed,
+// merged-pending, queued-digest) have no ChannelResults, so they fall back
+// to the original single row with Channel left empty.
 func (s *Service) recordAlert(req AlertRequest, res Result, payload string) {
+	outcome := string(res.Status)
+	if res.Error != nil {
+		outcome = "error"
+	}
+	metrics.AlertOutcomesTotal.WithLabelValues(outcome).Inc()
+
 	if s.store == nil {
 		return
 	}
+
+	channels := res.Channels
+	if len(channels) == 0 {
+		channels = []ChannelResult{{}}
+	}
 	ts := time.Now().Unix()
-	rec := store.AlertRecord{
-		TS:              ts,
-		Priority:        string(req.Priority),
-		GroupName:       req.Group,
-		Title:           req.Title,
-		DedupKey:        req.DedupKey,
-		MergeKey:        req.MergeKey,
-		Status:          string(res.Status),
-		Channel:         "dingtalk",
-		DingTalkErrCode: res.DingTalkErrCode,
-		DingTalkErrMsg:  res.DingTalkErrMsg,
-		PayloadMD:       payload,
-	}
-	if err := s.store.InsertAlert(rec); err != nil {
-		log.Printf("insert alert record error: %v", err)
+	for _, cr := range channels {
+		rec := store.AlertRecord{
+			TS:        ts,
+			Priority:  string(req.Priority),
+			GroupName: req.Group,
+			Title:     req.Title,
+			DedupKey:  req.DedupKey,
+			MergeKey:  req.MergeKey,
+			Status:    string(res.Status),
+			Channel:   cr.Channel,
+			PayloadMD: payload,
+			CreatedAt: time.Now().Format(time.RFC3339),
+		}
+		if cr.Channel == "dingtalk" {
+			rec.DingTalkErrCode = cr.Response.Code
+			rec.DingTalkErrMsg = cr.Response.Detail
+		}
+		if err := s.store.InsertAlert(rec); err != nil {
+			log.Printf("insert alert record error: %v", err)
+		}
+		s.broker.Publish(rec)
 	}
 
 	evt := store.EventRecord{
@@ -334,6 +953,21 @@ func maxPriority(alerts []AlertRequest) Priority {
 	return p
 }
 
+// maxDigestPriority is maxPriority over every group flushDigest is about to
+// send, so a channel's SeverityFilter sees the highest priority actually
+// contained in the digest instead of an assumed PriorityLow - a med alert,
+// or a high alert that timed out waiting for a scheduler token, both land
+// in the digest bucket same as any low-priority alert.
+func maxDigestPriority(groups map[string][]AlertRequest) Priority {
+	p := PriorityLow
+	for _, alerts := range groups {
+		if gp := maxPriority(alerts); rank(gp) > rank(p) {
+			p = gp
+		}
+	}
+	return p
+}
+
 func rank(p Priority) int {
 	switch p {
 	case PriorityHigh:
@@ -514,3 +1148,19 @@ func (t *TokenBucket) refillLocked() {
 	}
 	t.lastRefill = now
 }
+
+// tokensSnapshot returns the bucket's current token count after applying
+// any pending refill, for reporting (e.g. Scheduler.Stats) without
+// consuming a token.
+func (t *TokenBucket) tokensSnapshot() float64 {
+	if t == nil {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.disabled {
+		return -1
+	}
+	t.refillLocked()
+	return t.tokens
+}
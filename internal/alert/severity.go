@@ -0,0 +1,28 @@
+package alert
+
+import "context"
+
+var severityRank = map[string]int{string(PriorityLow): 0, string(PriorityMed): 1, string(PriorityHigh): 2}
+
+// SeverityFilter wraps a Notifier so Send is a no-op below minSeverity —
+// e.g. "only high goes to Telegram" — the same decorator shape
+// notifier.SeverityFilter and market.NewRateLimitedProvider use.
+type SeverityFilter struct {
+	inner       Notifier
+	minSeverity string
+}
+
+// NewSeverityFilter wraps inner, dropping Send calls below minSeverity. An
+// empty/unrecognized minSeverity is treated as "low" (no filtering).
+func NewSeverityFilter(inner Notifier, minSeverity string) *SeverityFilter {
+	return &SeverityFilter{inner: inner, minSeverity: minSeverity}
+}
+
+func (f *SeverityFilter) Name() string { return f.inner.Name() }
+
+func (f *SeverityFilter) Send(ctx context.Context, title, markdown string, meta map[string]string) (Response, error) {
+	if severityRank[meta["severity"]] < severityRank[f.minSeverity] {
+		return Response{}, nil
+	}
+	return f.inner.Send(ctx, title, markdown, meta)
+}
@@ -0,0 +1,80 @@
+package alert
+
+import (
+	"regexp"
+	"time"
+)
+
+// Matcher tests one field of an AlertRequest against Value using Op. A
+// Silence suppresses an alert only when every one of its Matchers passes.
+type Matcher struct {
+	Field string `json:"field"` // "group", "title", or "priority"
+	Op    string `json:"op"`    // "=", "!=", "=~", "!~"
+	Value string `json:"value"`
+}
+
+// Silence suppresses alerts matching every Matcher while StartsAt <= now <
+// EndsAt, modeled on Alertmanager's silences.
+type Silence struct {
+	ID        int64     `json:"id"`
+	Matchers  []Matcher `json:"matchers"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	CreatedBy string    `json:"created_by"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (m Matcher) fieldValue(req AlertRequest) string {
+	switch m.Field {
+	case "group":
+		return req.Group
+	case "title":
+		return req.Title
+	case "priority":
+		return string(req.Priority)
+	default:
+		return ""
+	}
+}
+
+// matches reports whether req satisfies m. An invalid regexp in a =~/!~
+// matcher never matches, rather than erroring, since a Silence is
+// evaluated on every Handle call and has no path to surface an error.
+func (m Matcher) matches(req AlertRequest) bool {
+	v := m.fieldValue(req)
+	switch m.Op {
+	case "=":
+		return v == m.Value
+	case "!=":
+		return v != m.Value
+	case "=~":
+		re, err := regexp.Compile(m.Value)
+		return err == nil && re.MatchString(v)
+	case "!~":
+		re, err := regexp.Compile(m.Value)
+		return err == nil && !re.MatchString(v)
+	default:
+		return false
+	}
+}
+
+// active reports whether the silence's window contains now.
+func (s Silence) active(now time.Time) bool {
+	return !now.Before(s.StartsAt) && now.Before(s.EndsAt)
+}
+
+// matchesAll reports whether req satisfies every one of the silence's
+// matchers. A silence with no matchers never matches anything, rather than
+// silencing every alert by vacuous truth.
+func (s Silence) matchesAll(req AlertRequest) bool {
+	if len(s.Matchers) == 0 {
+		return false
+	}
+	for _, m := range s.Matchers {
+		if !m.matches(req) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,31 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ai-trading-assistant/internal/push/slack"
+)
+
+// SlackNotifier adapts the shared push/slack.Client to alert.Notifier.
+type SlackNotifier struct {
+	client *slack.Client
+}
+
+func NewSlackNotifier(webhookURL string, timeout time.Duration) *SlackNotifier {
+	return &SlackNotifier{client: slack.NewClient(webhookURL, timeout)}
+}
+
+func (n *SlackNotifier) Name() string { return "slack" }
+
+func (n *SlackNotifier) Send(ctx context.Context, title, markdown string, meta map[string]string) (Response, error) {
+	status, err := n.client.Send(ctx, title, markdown)
+	if err != nil {
+		return Response{}, err
+	}
+	if status >= 300 {
+		return Response{Code: status}, fmt.Errorf("slack webhook returned status %d", status)
+	}
+	return Response{Code: status}, nil
+}
@@ -0,0 +1,63 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+// SMTPNotifier delivers alerts as plain-text email via a standard SMTP
+// relay. It has no concept of a structured response, so Response is left
+// zero-valued on success.
+type SMTPNotifier struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+func NewSMTPNotifier(host string, port int, username, password, from string, to []string) *SMTPNotifier {
+	return &SMTPNotifier{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+		to:       to,
+	}
+}
+
+func (n *SMTPNotifier) Name() string { return "smtp" }
+
+func (n *SMTPNotifier) Send(ctx context.Context, title, markdown string, meta map[string]string) (Response, error) {
+	if n.host == "" || len(n.to) == 0 {
+		return Response{}, fmt.Errorf("smtp host or recipients are empty")
+	}
+
+	addr := fmt.Sprintf("%s:%d", n.host, n.port)
+	msg := buildMIMEMessage(n.from, n.to, title, markdown)
+
+	var auth smtp.Auth
+	if n.username != "" {
+		auth = smtp.PlainAuth("", n.username, n.password, n.host)
+	}
+	if err := smtp.SendMail(addr, auth, n.from, n.to, msg); err != nil {
+		return Response{}, fmt.Errorf("send mail: %w", err)
+	}
+	return Response{}, nil
+}
+
+func buildMIMEMessage(from string, to []string, subject, body string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(body)
+	return []byte(b.String())
+}
@@ -0,0 +1,44 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"ai-trading-assistant/internal/push/webhook"
+)
+
+// WebhookNotifier adapts the shared push/webhook.Client to alert.Notifier.
+// When a secret is configured, the shared client signs the raw body with
+// HMAC-SHA256 keyed by the secret and sends the hex digest in an
+// X-Signature header, so the receiver can verify the request came from us.
+// Unlike DingTalk/Lark signing, no timestamp goes into the signature.
+type WebhookNotifier struct {
+	client *webhook.Client
+}
+
+func NewWebhookNotifier(url, secret string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{client: webhook.NewClient(url, secret, timeout)}
+}
+
+func (n *WebhookNotifier) Name() string { return "webhook" }
+
+func (n *WebhookNotifier) Send(ctx context.Context, title, markdown string, meta map[string]string) (Response, error) {
+	body, err := json.Marshal(map[string]any{
+		"title":    title,
+		"markdown": markdown,
+		"meta":     meta,
+	})
+	if err != nil {
+		return Response{}, fmt.Errorf("marshal request: %w", err)
+	}
+	status, err := n.client.Post(ctx, body)
+	if err != nil {
+		return Response{}, err
+	}
+	if status >= 300 {
+		return Response{Code: status}, fmt.Errorf("webhook returned status %d", status)
+	}
+	return Response{Code: status}, nil
+}
@@ -0,0 +1,30 @@
+package apiv1pb
+
+import "encoding/json"
+
+// jsonCodec is a protoc-free substitute for grpc's default "proto" wire
+// codec: apiv1pb's messages (see messages.go) are plain structs, not
+// generated protobuf types, so there is nothing for the real codec to
+// marshal. Callers must opt into it explicitly with
+// grpc.ForceServerCodec(Codec) when building the server (see
+// cmd/server/main.go) and, symmetrically, NewApiV1Client attaches
+// grpc.ForceCodec(Codec) to every outgoing call — this avoids relying on
+// package init order to override grpc's globally registered "proto" codec,
+// which would be fragile.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return "apiv1-json"
+}
+
+// Codec is the encoding.Codec ApiV1's server and client must be configured
+// with via grpc.ForceCodec, in place of the usual generated-protobuf codec.
+var Codec = jsonCodec{}
@@ -0,0 +1,356 @@
+// Package apiv1pb is the wire-type package for internal/api/grpc's ApiV1
+// service described by ../apiv1.proto.
+//
+// These are normally produced by `protoc` (see apiv1.proto's header
+// comment), but this tree has no protoc/protoc-gen-go toolchain available
+// to run that generation step, so the types below are a hand-written,
+// protoc-free stand-in: plain Go structs carrying the same fields as the
+// .proto messages, encoded over the wire as JSON rather than the protobuf
+// binary format (see codec.go). Keep this file's messages in sync with
+// apiv1.proto by hand until a real `make proto` step replaces it.
+//
+// Because these structs are marshaled as JSON instead of protobuf, a
+// protoc-generated client built from apiv1.proto in another language will
+// not be able to decode what Server actually sends — this package gives no
+// cross-language interop (the NOTE in apiv1.proto's header says the same),
+// only Go-to-Go gRPC-framing feature parity with the Hertz REST API. Running apiv1.proto through real codegen and deleting this file is
+// the remaining step.
+package apiv1pb
+
+// TestPushRequest mirrors the TestPushRequest message in apiv1.proto.
+type TestPushRequest struct {
+	Title    string `json:"title,omitempty"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+func (m *TestPushRequest) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+func (m *TestPushRequest) GetMarkdown() string {
+	if m != nil {
+		return m.Markdown
+	}
+	return ""
+}
+
+type TestPushResponse struct {
+	DingtalkErrcode int32  `json:"dingtalk_errcode,omitempty"`
+	DingtalkErrmsg  string `json:"dingtalk_errmsg,omitempty"`
+}
+
+type AlertRequest struct {
+	Priority string `json:"priority,omitempty"`
+	Group    string `json:"group,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Markdown string `json:"markdown,omitempty"`
+	DedupKey string `json:"dedup_key,omitempty"`
+	MergeKey string `json:"merge_key,omitempty"`
+	Silent   bool   `json:"silent,omitempty"`
+}
+
+func (m *AlertRequest) GetPriority() string {
+	if m != nil {
+		return m.Priority
+	}
+	return ""
+}
+
+func (m *AlertRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *AlertRequest) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+func (m *AlertRequest) GetMarkdown() string {
+	if m != nil {
+		return m.Markdown
+	}
+	return ""
+}
+
+func (m *AlertRequest) GetDedupKey() string {
+	if m != nil {
+		return m.DedupKey
+	}
+	return ""
+}
+
+func (m *AlertRequest) GetMergeKey() string {
+	if m != nil {
+		return m.MergeKey
+	}
+	return ""
+}
+
+func (m *AlertRequest) GetSilent() bool {
+	if m != nil {
+		return m.Silent
+	}
+	return false
+}
+
+type AlertResult struct {
+	Status          string `json:"status,omitempty"`
+	Error           string `json:"error,omitempty"`
+	DingtalkErrcode int32  `json:"dingtalk_errcode,omitempty"`
+	DingtalkErrmsg  string `json:"dingtalk_errmsg,omitempty"`
+}
+
+type HandleAlertRequest struct {
+	Alert *AlertRequest `json:"alert,omitempty"`
+}
+
+func (m *HandleAlertRequest) GetAlert() *AlertRequest {
+	if m != nil {
+		return m.Alert
+	}
+	return nil
+}
+
+type HandleAlertResponse struct {
+	Result *AlertResult `json:"result,omitempty"`
+}
+
+type QueryAlertsRequest struct {
+	Date   string `json:"date,omitempty"`
+	Status string `json:"status,omitempty"`
+	Group  string `json:"group,omitempty"`
+	Limit  int32  `json:"limit,omitempty"`
+	Offset int32  `json:"offset,omitempty"`
+}
+
+func (m *QueryAlertsRequest) GetDate() string {
+	if m != nil {
+		return m.Date
+	}
+	return ""
+}
+
+func (m *QueryAlertsRequest) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *QueryAlertsRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *QueryAlertsRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *QueryAlertsRequest) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type AlertRecord struct {
+	Ts              int64  `json:"ts,omitempty"`
+	Priority        string `json:"priority,omitempty"`
+	Group           string `json:"group,omitempty"`
+	Title           string `json:"title,omitempty"`
+	DedupKey        string `json:"dedup_key,omitempty"`
+	MergeKey        string `json:"merge_key,omitempty"`
+	Status          string `json:"status,omitempty"`
+	Channel         string `json:"channel,omitempty"`
+	DingtalkErrcode int32  `json:"dingtalk_errcode,omitempty"`
+	DingtalkErrmsg  string `json:"dingtalk_errmsg,omitempty"`
+	PayloadMd       string `json:"payload_md,omitempty"`
+	CreatedAt       string `json:"created_at,omitempty"`
+}
+
+type QueryAlertsResponse struct {
+	Items []*AlertRecord `json:"items,omitempty"`
+}
+
+type GenerateQuotesRequest struct {
+	RawSymbols string `json:"raw_symbols,omitempty"`
+}
+
+func (m *GenerateQuotesRequest) GetRawSymbols() string {
+	if m != nil {
+		return m.RawSymbols
+	}
+	return ""
+}
+
+type Quote struct {
+	Symbol    string  `json:"symbol,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	Price     float64 `json:"price,omitempty"`
+	ChangePct float64 `json:"change_pct,omitempty"`
+	Volume    float64 `json:"volume,omitempty"`
+	Ts        int64   `json:"ts,omitempty"`
+	Source    string  `json:"source,omitempty"`
+}
+
+type GenerateQuotesResponse struct {
+	Quotes   []*Quote `json:"quotes,omitempty"`
+	Stale    bool     `json:"stale,omitempty"`
+	Source   string   `json:"source,omitempty"`
+	SourceTs int64    `json:"source_ts,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type GenerateSnapshotRequest struct {
+	Symbol    string  `json:"symbol,omitempty"`
+	Price     float64 `json:"price,omitempty"`
+	ChangePct float64 `json:"change_pct,omitempty"`
+	Volume    float64 `json:"volume,omitempty"`
+	Ts        int64   `json:"ts,omitempty"`
+}
+
+func (m *GenerateSnapshotRequest) GetSymbol() string {
+	if m != nil {
+		return m.Symbol
+	}
+	return ""
+}
+
+func (m *GenerateSnapshotRequest) GetPrice() float64 {
+	if m != nil {
+		return m.Price
+	}
+	return 0
+}
+
+func (m *GenerateSnapshotRequest) GetChangePct() float64 {
+	if m != nil {
+		return m.ChangePct
+	}
+	return 0
+}
+
+func (m *GenerateSnapshotRequest) GetVolume() float64 {
+	if m != nil {
+		return m.Volume
+	}
+	return 0
+}
+
+func (m *GenerateSnapshotRequest) GetTs() int64 {
+	if m != nil {
+		return m.Ts
+	}
+	return 0
+}
+
+type GenerateSnapshotResponse struct{}
+
+type EvaluateRiskRequest struct {
+	EventId int64 `json:"event_id,omitempty"`
+}
+
+func (m *EvaluateRiskRequest) GetEventId() int64 {
+	if m != nil {
+		return m.EventId
+	}
+	return 0
+}
+
+type RiskDecision struct {
+	RiskLevel  int32    `json:"risk_level,omitempty"`
+	Severity   string   `json:"severity,omitempty"`
+	OneLiner   string   `json:"one_liner,omitempty"`
+	Why        []string `json:"why,omitempty"`
+	ActionHint []string `json:"action_hint,omitempty"`
+	Confidence float64  `json:"confidence,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+type EvaluateRiskResponse struct {
+	Decision *RiskDecision `json:"decision,omitempty"`
+	Markdown string        `json:"markdown,omitempty"`
+}
+
+type GeneratePlanRequest struct {
+	Date       string `json:"date,omitempty"`
+	RawSymbols string `json:"raw_symbols,omitempty"`
+}
+
+func (m *GeneratePlanRequest) GetDate() string {
+	if m != nil {
+		return m.Date
+	}
+	return ""
+}
+
+func (m *GeneratePlanRequest) GetRawSymbols() string {
+	if m != nil {
+		return m.RawSymbols
+	}
+	return ""
+}
+
+type TradeItem struct {
+	Symbol      string  `json:"symbol,omitempty"`
+	Trigger     string  `json:"trigger,omitempty"`
+	Invalidate  string  `json:"invalidate,omitempty"`
+	PositionPct float64 `json:"position_pct,omitempty"`
+	StopLoss    string  `json:"stop_loss,omitempty"`
+}
+
+type Plan struct {
+	MarketBias     string       `json:"market_bias,omitempty"`
+	MaxExposurePct float64      `json:"max_exposure_pct,omitempty"`
+	TradePool      []*TradeItem `json:"trade_pool,omitempty"`
+	WatchPool      []string     `json:"watch_pool,omitempty"`
+	BanList        []string     `json:"ban_list,omitempty"`
+}
+
+type GeneratePlanResponse struct {
+	Mode     string   `json:"mode,omitempty"`
+	Plan     *Plan    `json:"plan,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type ConfirmPlanRequest struct {
+	Date string `json:"date,omitempty"`
+}
+
+func (m *ConfirmPlanRequest) GetDate() string {
+	if m != nil {
+		return m.Date
+	}
+	return ""
+}
+
+type ConfirmPlanResponse struct{}
+
+type GetPlanRequest struct {
+	Date string `json:"date,omitempty"`
+}
+
+func (m *GetPlanRequest) GetDate() string {
+	if m != nil {
+		return m.Date
+	}
+	return ""
+}
+
+type GetPlanResponse struct {
+	Plan      *Plan `json:"plan,omitempty"`
+	Confirmed bool  `json:"confirmed,omitempty"`
+}
@@ -0,0 +1,331 @@
+package apiv1pb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ApiV1Client is the client API for the ApiV1 service described in
+// apiv1.proto.
+type ApiV1Client interface {
+	TestPush(ctx context.Context, in *TestPushRequest, opts ...grpc.CallOption) (*TestPushResponse, error)
+	HandleAlert(ctx context.Context, in *HandleAlertRequest, opts ...grpc.CallOption) (*HandleAlertResponse, error)
+	QueryAlerts(ctx context.Context, in *QueryAlertsRequest, opts ...grpc.CallOption) (*QueryAlertsResponse, error)
+	GenerateQuotes(ctx context.Context, in *GenerateQuotesRequest, opts ...grpc.CallOption) (*GenerateQuotesResponse, error)
+	GenerateSnapshot(ctx context.Context, in *GenerateSnapshotRequest, opts ...grpc.CallOption) (*GenerateSnapshotResponse, error)
+	EvaluateRisk(ctx context.Context, in *EvaluateRiskRequest, opts ...grpc.CallOption) (*EvaluateRiskResponse, error)
+	GeneratePlan(ctx context.Context, in *GeneratePlanRequest, opts ...grpc.CallOption) (*GeneratePlanResponse, error)
+	ConfirmPlan(ctx context.Context, in *ConfirmPlanRequest, opts ...grpc.CallOption) (*ConfirmPlanResponse, error)
+	GetPlan(ctx context.Context, in *GetPlanRequest, opts ...grpc.CallOption) (*GetPlanResponse, error)
+}
+
+type apiV1Client struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewApiV1Client wraps cc, attaching Codec to every call so callers don't
+// need to remember grpc.ForceCodec(Codec) themselves (see codec.go).
+func NewApiV1Client(cc grpc.ClientConnInterface) ApiV1Client {
+	return &apiV1Client{cc}
+}
+
+func (c *apiV1Client) invoke(ctx context.Context, method string, in, out any, opts ...grpc.CallOption) error {
+	opts = append([]grpc.CallOption{grpc.ForceCodec(Codec)}, opts...)
+	return c.cc.Invoke(ctx, method, in, out, opts...)
+}
+
+func (c *apiV1Client) TestPush(ctx context.Context, in *TestPushRequest, opts ...grpc.CallOption) (*TestPushResponse, error) {
+	out := new(TestPushResponse)
+	if err := c.invoke(ctx, "/apiv1.ApiV1/TestPush", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiV1Client) HandleAlert(ctx context.Context, in *HandleAlertRequest, opts ...grpc.CallOption) (*HandleAlertResponse, error) {
+	out := new(HandleAlertResponse)
+	if err := c.invoke(ctx, "/apiv1.ApiV1/HandleAlert", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiV1Client) QueryAlerts(ctx context.Context, in *QueryAlertsRequest, opts ...grpc.CallOption) (*QueryAlertsResponse, error) {
+	out := new(QueryAlertsResponse)
+	if err := c.invoke(ctx, "/apiv1.ApiV1/QueryAlerts", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiV1Client) GenerateQuotes(ctx context.Context, in *GenerateQuotesRequest, opts ...grpc.CallOption) (*GenerateQuotesResponse, error) {
+	out := new(GenerateQuotesResponse)
+	if err := c.invoke(ctx, "/apiv1.ApiV1/GenerateQuotes", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiV1Client) GenerateSnapshot(ctx context.Context, in *GenerateSnapshotRequest, opts ...grpc.CallOption) (*GenerateSnapshotResponse, error) {
+	out := new(GenerateSnapshotResponse)
+	if err := c.invoke(ctx, "/apiv1.ApiV1/GenerateSnapshot", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiV1Client) EvaluateRisk(ctx context.Context, in *EvaluateRiskRequest, opts ...grpc.CallOption) (*EvaluateRiskResponse, error) {
+	out := new(EvaluateRiskResponse)
+	if err := c.invoke(ctx, "/apiv1.ApiV1/EvaluateRisk", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiV1Client) GeneratePlan(ctx context.Context, in *GeneratePlanRequest, opts ...grpc.CallOption) (*GeneratePlanResponse, error) {
+	out := new(GeneratePlanResponse)
+	if err := c.invoke(ctx, "/apiv1.ApiV1/GeneratePlan", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiV1Client) ConfirmPlan(ctx context.Context, in *ConfirmPlanRequest, opts ...grpc.CallOption) (*ConfirmPlanResponse, error) {
+	out := new(ConfirmPlanResponse)
+	if err := c.invoke(ctx, "/apiv1.ApiV1/ConfirmPlan", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiV1Client) GetPlan(ctx context.Context, in *GetPlanRequest, opts ...grpc.CallOption) (*GetPlanResponse, error) {
+	out := new(GetPlanResponse)
+	if err := c.invoke(ctx, "/apiv1.ApiV1/GetPlan", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ApiV1Server is the server API for the ApiV1 service described in
+// apiv1.proto. internal/api/grpc.Server implements this against a
+// *service.Service.
+type ApiV1Server interface {
+	TestPush(context.Context, *TestPushRequest) (*TestPushResponse, error)
+	HandleAlert(context.Context, *HandleAlertRequest) (*HandleAlertResponse, error)
+	QueryAlerts(context.Context, *QueryAlertsRequest) (*QueryAlertsResponse, error)
+	GenerateQuotes(context.Context, *GenerateQuotesRequest) (*GenerateQuotesResponse, error)
+	GenerateSnapshot(context.Context, *GenerateSnapshotRequest) (*GenerateSnapshotResponse, error)
+	EvaluateRisk(context.Context, *EvaluateRiskRequest) (*EvaluateRiskResponse, error)
+	GeneratePlan(context.Context, *GeneratePlanRequest) (*GeneratePlanResponse, error)
+	ConfirmPlan(context.Context, *ConfirmPlanRequest) (*ConfirmPlanResponse, error)
+	GetPlan(context.Context, *GetPlanRequest) (*GetPlanResponse, error)
+	mustEmbedUnimplementedApiV1Server()
+}
+
+// UnimplementedApiV1Server must be embedded by Server implementations so
+// that adding a new RPC to apiv1.proto doesn't break them at compile time.
+type UnimplementedApiV1Server struct{}
+
+func (UnimplementedApiV1Server) TestPush(context.Context, *TestPushRequest) (*TestPushResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TestPush not implemented")
+}
+
+func (UnimplementedApiV1Server) HandleAlert(context.Context, *HandleAlertRequest) (*HandleAlertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HandleAlert not implemented")
+}
+
+func (UnimplementedApiV1Server) QueryAlerts(context.Context, *QueryAlertsRequest) (*QueryAlertsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryAlerts not implemented")
+}
+
+func (UnimplementedApiV1Server) GenerateQuotes(context.Context, *GenerateQuotesRequest) (*GenerateQuotesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateQuotes not implemented")
+}
+
+func (UnimplementedApiV1Server) GenerateSnapshot(context.Context, *GenerateSnapshotRequest) (*GenerateSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateSnapshot not implemented")
+}
+
+func (UnimplementedApiV1Server) EvaluateRisk(context.Context, *EvaluateRiskRequest) (*EvaluateRiskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EvaluateRisk not implemented")
+}
+
+func (UnimplementedApiV1Server) GeneratePlan(context.Context, *GeneratePlanRequest) (*GeneratePlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GeneratePlan not implemented")
+}
+
+func (UnimplementedApiV1Server) ConfirmPlan(context.Context, *ConfirmPlanRequest) (*ConfirmPlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmPlan not implemented")
+}
+
+func (UnimplementedApiV1Server) GetPlan(context.Context, *GetPlanRequest) (*GetPlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlan not implemented")
+}
+
+func (UnimplementedApiV1Server) mustEmbedUnimplementedApiV1Server() {}
+
+// RegisterApiV1Server registers srv against s. Callers must have built s
+// with grpc.ForceServerCodec(Codec) (see codec.go's doc comment); ApiV1's
+// messages aren't real protobuf types, so the default codec can't handle
+// them.
+func RegisterApiV1Server(s grpc.ServiceRegistrar, srv ApiV1Server) {
+	s.RegisterService(&ApiV1_ServiceDesc, srv)
+}
+
+func _ApiV1_TestPush_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(TestPushRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiV1Server).TestPush(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/apiv1.ApiV1/TestPush"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(ApiV1Server).TestPush(ctx, req.(*TestPushRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiV1_HandleAlert_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(HandleAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiV1Server).HandleAlert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/apiv1.ApiV1/HandleAlert"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(ApiV1Server).HandleAlert(ctx, req.(*HandleAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiV1_QueryAlerts_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(QueryAlertsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiV1Server).QueryAlerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/apiv1.ApiV1/QueryAlerts"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(ApiV1Server).QueryAlerts(ctx, req.(*QueryAlertsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiV1_GenerateQuotes_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(GenerateQuotesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiV1Server).GenerateQuotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/apiv1.ApiV1/GenerateQuotes"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(ApiV1Server).GenerateQuotes(ctx, req.(*GenerateQuotesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiV1_GenerateSnapshot_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(GenerateSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiV1Server).GenerateSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/apiv1.ApiV1/GenerateSnapshot"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(ApiV1Server).GenerateSnapshot(ctx, req.(*GenerateSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiV1_EvaluateRisk_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(EvaluateRiskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiV1Server).EvaluateRisk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/apiv1.ApiV1/EvaluateRisk"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(ApiV1Server).EvaluateRisk(ctx, req.(*EvaluateRiskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiV1_GeneratePlan_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(GeneratePlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiV1Server).GeneratePlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/apiv1.ApiV1/GeneratePlan"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(ApiV1Server).GeneratePlan(ctx, req.(*GeneratePlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiV1_ConfirmPlan_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(ConfirmPlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiV1Server).ConfirmPlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/apiv1.ApiV1/ConfirmPlan"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(ApiV1Server).ConfirmPlan(ctx, req.(*ConfirmPlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiV1_GetPlan_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(GetPlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiV1Server).GetPlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/apiv1.ApiV1/GetPlan"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(ApiV1Server).GetPlan(ctx, req.(*GetPlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ApiV1_ServiceDesc is the grpc.ServiceDesc for ApiV1, matching the RPCs
+// declared in apiv1.proto.
+var ApiV1_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.ApiV1",
+	HandlerType: (*ApiV1Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "TestPush", Handler: _ApiV1_TestPush_Handler},
+		{MethodName: "HandleAlert", Handler: _ApiV1_HandleAlert_Handler},
+		{MethodName: "QueryAlerts", Handler: _ApiV1_QueryAlerts_Handler},
+		{MethodName: "GenerateQuotes", Handler: _ApiV1_GenerateQuotes_Handler},
+		{MethodName: "GenerateSnapshot", Handler: _ApiV1_GenerateSnapshot_Handler},
+		{MethodName: "EvaluateRisk", Handler: _ApiV1_EvaluateRisk_Handler},
+		{MethodName: "GeneratePlan", Handler: _ApiV1_GeneratePlan_Handler},
+		{MethodName: "ConfirmPlan", Handler: _ApiV1_ConfirmPlan_Handler},
+		{MethodName: "GetPlan", Handler: _ApiV1_GetPlan_Handler},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "apiv1.proto",
+}
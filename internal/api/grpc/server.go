@@ -0,0 +1,256 @@
+// Package grpc exposes the same operations as the Hertz REST API
+// (internal/api) over gRPC, delegating to internal/api/service so both
+// transports share one implementation of the business logic.
+//
+// apiv1pb normally would be produced by `protoc` from apiv1.proto, but
+// this tree has no protoc toolchain available, so apiv1pb is a
+// hand-written, protoc-free stand-in (plain structs plus a JSON codec —
+// see apiv1pb/codec.go) that Server satisfies directly. That stand-in is
+// wire-compatible with itself but NOT with a standard protoc-generated
+// client in another language: such a client sends/expects protobuf binary
+// framed per apiv1.proto, while Server is forced onto a JSON codec, so the
+// request body fails to decode. Until apiv1.proto is actually run through
+// protoc, this subsystem gives Go-to-Go gRPC-framing feature parity, not
+// cross-language interop (see the NOTE in apiv1.proto's header).
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"ai-trading-assistant/internal/alert"
+	"ai-trading-assistant/internal/api/grpc/apiv1pb"
+	"ai-trading-assistant/internal/api/service"
+	"ai-trading-assistant/internal/planagent"
+	"ai-trading-assistant/internal/riskagent"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcStatus maps a transport-neutral service.Status onto the grpc codes
+// space, the gRPC counterpart of router.go's httpStatus table.
+func grpcStatus(st service.Status) codes.Code {
+	switch st {
+	case service.StatusOK:
+		return codes.OK
+	case service.StatusInvalidArgument:
+		return codes.InvalidArgument
+	case service.StatusNotFound:
+		return codes.NotFound
+	case service.StatusUnavailable:
+		return codes.Unavailable
+	case service.StatusInternal:
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
+
+// Server implements apiv1pb.ApiV1Server against a *service.Service.
+type Server struct {
+	apiv1pb.UnimplementedApiV1Server
+
+	svc *service.Service
+}
+
+func New(svc *service.Service) *Server {
+	return &Server{svc: svc}
+}
+
+func (s *Server) TestPush(ctx context.Context, req *apiv1pb.TestPushRequest) (*apiv1pb.TestPushResponse, error) {
+	out, st, err := s.svc.TestPush(ctx, service.TestPushRequest{
+		Title:    req.GetTitle(),
+		Markdown: req.GetMarkdown(),
+	})
+	if err != nil {
+		return nil, status.Error(grpcStatus(st), err.Error())
+	}
+	return &apiv1pb.TestPushResponse{
+		DingtalkErrcode: int32(out.DingTalkErrCode),
+		DingtalkErrmsg:  out.DingTalkErrMsg,
+	}, nil
+}
+
+func (s *Server) HandleAlert(ctx context.Context, req *apiv1pb.HandleAlertRequest) (*apiv1pb.HandleAlertResponse, error) {
+	a := req.GetAlert()
+	out, st, err := s.svc.HandleAlert(ctx, service.HandleAlertRequest{
+		Alert: alert.AlertRequest{
+			Priority: alert.Priority(a.GetPriority()),
+			Group:    a.GetGroup(),
+			Title:    a.GetTitle(),
+			Markdown: a.GetMarkdown(),
+			DedupKey: a.GetDedupKey(),
+			MergeKey: a.GetMergeKey(),
+			Silent:   a.GetSilent(),
+		},
+	})
+	if err != nil {
+		return nil, status.Error(grpcStatus(st), err.Error())
+	}
+	errMsg := ""
+	if out.Result.Error != nil {
+		errMsg = out.Result.Error.Error()
+	}
+	return &apiv1pb.HandleAlertResponse{
+		Result: &apiv1pb.AlertResult{
+			Status:          string(out.Result.Status),
+			Error:           errMsg,
+			DingtalkErrcode: int32(out.Result.DingTalkErrCode),
+			DingtalkErrmsg:  out.Result.DingTalkErrMsg,
+		},
+	}, nil
+}
+
+func (s *Server) QueryAlerts(ctx context.Context, req *apiv1pb.QueryAlertsRequest) (*apiv1pb.QueryAlertsResponse, error) {
+	out, st, err := s.svc.QueryAlerts(ctx, service.QueryAlertsRequest{
+		Date:   req.GetDate(),
+		Status: req.GetStatus(),
+		Group:  req.GetGroup(),
+		Limit:  int(req.GetLimit()),
+		Offset: int(req.GetOffset()),
+	})
+	if err != nil {
+		return nil, status.Error(grpcStatus(st), err.Error())
+	}
+	items := make([]*apiv1pb.AlertRecord, 0, len(out.Items))
+	for _, rec := range out.Items {
+		items = append(items, &apiv1pb.AlertRecord{
+			Ts:              rec.TS,
+			Priority:        rec.Priority,
+			Group:           rec.GroupName,
+			Title:           rec.Title,
+			DedupKey:        rec.DedupKey,
+			MergeKey:        rec.MergeKey,
+			Status:          rec.Status,
+			Channel:         rec.Channel,
+			DingtalkErrcode: int32(rec.DingTalkErrCode),
+			DingtalkErrmsg:  rec.DingTalkErrMsg,
+			PayloadMd:       rec.PayloadMD,
+			CreatedAt:       rec.CreatedAt,
+		})
+	}
+	return &apiv1pb.QueryAlertsResponse{Items: items}, nil
+}
+
+func (s *Server) GenerateQuotes(ctx context.Context, req *apiv1pb.GenerateQuotesRequest) (*apiv1pb.GenerateQuotesResponse, error) {
+	out, st, err := s.svc.GenerateQuotes(ctx, service.GenerateQuotesRequest{
+		RawSymbols: req.GetRawSymbols(),
+	})
+	if err != nil {
+		return nil, status.Error(grpcStatus(st), err.Error())
+	}
+	quotes := make([]*apiv1pb.Quote, 0, len(out.Quotes))
+	for _, q := range out.Quotes {
+		quotes = append(quotes, &apiv1pb.Quote{
+			Symbol:    q.Symbol,
+			Name:      q.Name,
+			Price:     q.Price,
+			ChangePct: q.ChangePct,
+			Volume:    q.Volume,
+			Ts:        q.TS,
+			Source:    q.Source,
+		})
+	}
+	return &apiv1pb.GenerateQuotesResponse{
+		Quotes:   quotes,
+		Stale:    out.Stale,
+		Source:   out.Source,
+		SourceTs: out.SourceTS,
+		Warnings: out.Warnings,
+	}, nil
+}
+
+func (s *Server) GenerateSnapshot(ctx context.Context, req *apiv1pb.GenerateSnapshotRequest) (*apiv1pb.GenerateSnapshotResponse, error) {
+	_, st, err := s.svc.GenerateSnapshot(ctx, service.GenerateSnapshotRequest{
+		Symbol:    req.GetSymbol(),
+		Price:     req.GetPrice(),
+		ChangePct: req.GetChangePct(),
+		Volume:    req.GetVolume(),
+		TS:        req.GetTs(),
+	})
+	if err != nil {
+		return nil, status.Error(grpcStatus(st), err.Error())
+	}
+	return &apiv1pb.GenerateSnapshotResponse{}, nil
+}
+
+func (s *Server) EvaluateRisk(ctx context.Context, req *apiv1pb.EvaluateRiskRequest) (*apiv1pb.EvaluateRiskResponse, error) {
+	if req.GetEventId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, fmt.Errorf("event_id is required").Error())
+	}
+	out, st, err := s.svc.EvaluateRisk(ctx, service.EvaluateRiskRequest{EventID: req.GetEventId()})
+	if err != nil {
+		return nil, status.Error(grpcStatus(st), err.Error())
+	}
+	return &apiv1pb.EvaluateRiskResponse{
+		Decision: decisionToPB(out.Decision),
+		Markdown: out.Markdown,
+	}, nil
+}
+
+func (s *Server) GeneratePlan(ctx context.Context, req *apiv1pb.GeneratePlanRequest) (*apiv1pb.GeneratePlanResponse, error) {
+	out, st, err := s.svc.GeneratePlan(ctx, service.GeneratePlanRequest{
+		Date:       req.GetDate(),
+		RawSymbols: req.GetRawSymbols(),
+	})
+	if err != nil {
+		return nil, status.Error(grpcStatus(st), err.Error())
+	}
+	return &apiv1pb.GeneratePlanResponse{
+		Mode:     out.Mode,
+		Plan:     planToPB(out.Plan),
+		Warnings: out.Warnings,
+	}, nil
+}
+
+func (s *Server) ConfirmPlan(ctx context.Context, req *apiv1pb.ConfirmPlanRequest) (*apiv1pb.ConfirmPlanResponse, error) {
+	_, st, err := s.svc.ConfirmPlan(ctx, service.ConfirmPlanRequest{Date: req.GetDate()})
+	if err != nil {
+		return nil, status.Error(grpcStatus(st), err.Error())
+	}
+	return &apiv1pb.ConfirmPlanResponse{}, nil
+}
+
+func (s *Server) GetPlan(ctx context.Context, req *apiv1pb.GetPlanRequest) (*apiv1pb.GetPlanResponse, error) {
+	out, st, err := s.svc.GetPlan(ctx, service.GetPlanRequest{Date: req.GetDate()})
+	if err != nil {
+		return nil, status.Error(grpcStatus(st), err.Error())
+	}
+	return &apiv1pb.GetPlanResponse{
+		Plan:      planToPB(out.Plan),
+		Confirmed: out.Confirmed,
+	}, nil
+}
+
+func decisionToPB(d riskagent.RiskDecision) *apiv1pb.RiskDecision {
+	return &apiv1pb.RiskDecision{
+		RiskLevel:  int32(d.RiskLevel),
+		Severity:   d.Severity,
+		OneLiner:   d.OneLiner,
+		Why:        d.Why,
+		ActionHint: d.ActionHint,
+		Confidence: d.Confidence,
+		Tags:       d.Tags,
+	}
+}
+
+func planToPB(p planagent.Plan) *apiv1pb.Plan {
+	items := make([]*apiv1pb.TradeItem, 0, len(p.TradePool))
+	for _, t := range p.TradePool {
+		items = append(items, &apiv1pb.TradeItem{
+			Symbol:      t.Symbol,
+			Trigger:     t.Trigger,
+			Invalidate:  t.Invalidate,
+			PositionPct: t.PositionPct,
+			StopLoss:    t.StopLoss,
+		})
+	}
+	return &apiv1pb.Plan{
+		MarketBias:     p.MarketBias,
+		MaxExposurePct: p.MaxExposurePct,
+		TradePool:      items,
+		WatchPool:      p.WatchPool,
+		BanList:        p.BanList,
+	}
+}
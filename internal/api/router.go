@@ -2,24 +2,22 @@ package api
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"ai-trading-assistant/internal/alert"
+	"ai-trading-assistant/internal/api/service"
 	"ai-trading-assistant/internal/engine"
 	"ai-trading-assistant/internal/market"
+	"ai-trading-assistant/internal/metrics"
 	"ai-trading-assistant/internal/planagent"
 	"ai-trading-assistant/internal/push/dingtalk"
 	"ai-trading-assistant/internal/riskagent"
 	"ai-trading-assistant/internal/store"
 
-	"database/sql"
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/app/server"
 )
@@ -37,20 +35,39 @@ type AlertResponse struct {
 	DingTalkErrMsg  string `json:"dingtalk_errmsg,omitempty"`
 }
 
+// httpStatus maps a transport-neutral service.Status onto the HTTP status
+// code table used across this API.
+func httpStatus(st service.Status) int {
+	switch st {
+	case service.StatusOK:
+		return http.StatusOK
+	case service.StatusInvalidArgument:
+		return http.StatusBadRequest
+	case service.StatusNotFound:
+		return http.StatusNotFound
+	case service.StatusUnavailable:
+		return http.StatusServiceUnavailable
+	case service.StatusInternal:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadGateway
+	}
+}
+
 func RegisterRoutes(h *server.Hertz, dt *dingtalk.Client, alertSvc *alert.Service, st *store.Store, mkt *market.Service, defaultSymbols []string, eng *engine.Engine, agent *riskagent.Agent, planAgent *planagent.Agent) {
+	svc := service.New(dt, alertSvc, st, mkt, eng, agent, planAgent, defaultSymbols)
+
+	h.Use(metrics.Middleware())
+
 	h.GET("/healthz", func(_ context.Context, c *app.RequestContext) {
 		c.JSON(200, map[string]bool{"ok": true})
 	})
 
-	h.POST("/api/v1/test/push", func(_ context.Context, c *app.RequestContext) {
-		if dt == nil {
-			c.JSON(http.StatusInternalServerError, map[string]any{
-				"ok":    false,
-				"error": "dingtalk client not configured",
-			})
-			return
-		}
+	h.GET("/metrics", metrics.Handler())
 
+	registerStreamRoutes(h, alertSvc, st, mkt, defaultSymbols)
+
+	h.POST("/api/v1/test/push", func(_ context.Context, c *app.RequestContext) {
 		var req TestPushRequest
 		if err := c.BindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, map[string]any{
@@ -60,44 +77,26 @@ func RegisterRoutes(h *server.Hertz, dt *dingtalk.Client, alertSvc *alert.Servic
 			return
 		}
 
-		resp, err := dt.SendMarkdown(context.Background(), req.Title, req.Markdown)
+		resp, code, err := svc.TestPush(context.Background(), service.TestPushRequest{Title: req.Title, Markdown: req.Markdown})
 		if err != nil {
-			log.Printf("dingtalk send error: %v", err)
-			c.JSON(http.StatusBadGateway, map[string]any{
+			log.Printf("test push error: %v", err)
+			c.JSON(httpStatus(code), map[string]any{
 				"ok":               false,
 				"error":            err.Error(),
-				"dingtalk_errcode": 0,
-				"dingtalk_errmsg":  "",
-			})
-			return
-		}
-
-		if resp.ErrCode != 0 {
-			c.JSON(http.StatusBadGateway, map[string]any{
-				"ok":               false,
-				"error":            "dingtalk returned error",
-				"dingtalk_errcode": resp.ErrCode,
-				"dingtalk_errmsg":  resp.ErrMsg,
+				"dingtalk_errcode": resp.DingTalkErrCode,
+				"dingtalk_errmsg":  resp.DingTalkErrMsg,
 			})
 			return
 		}
 
 		c.JSON(http.StatusOK, map[string]any{
 			"ok":               true,
-			"dingtalk_errcode": resp.ErrCode,
-			"dingtalk_errmsg":  resp.ErrMsg,
+			"dingtalk_errcode": resp.DingTalkErrCode,
+			"dingtalk_errmsg":  resp.DingTalkErrMsg,
 		})
 	})
 
 	h.POST("/api/v1/test/alert", func(_ context.Context, c *app.RequestContext) {
-		if alertSvc == nil {
-			c.JSON(http.StatusInternalServerError, map[string]any{
-				"ok":    false,
-				"error": "alert service not configured",
-			})
-			return
-		}
-
 		var req alert.AlertRequest
 		if err := c.BindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, map[string]any{
@@ -107,7 +106,15 @@ func RegisterRoutes(h *server.Hertz, dt *dingtalk.Client, alertSvc *alert.Servic
 			return
 		}
 
-		res := alertSvc.Handle(context.Background(), req)
+		out, code, err := svc.HandleAlert(context.Background(), service.HandleAlertRequest{Alert: req})
+		if err != nil {
+			c.JSON(httpStatus(code), map[string]any{
+				"ok":    false,
+				"error": err.Error(),
+			})
+			return
+		}
+		res := out.Result
 		resp := AlertResponse{
 			OK:              res.Error == nil,
 			Status:          string(res.Status),
@@ -120,6 +127,107 @@ func RegisterRoutes(h *server.Hertz, dt *dingtalk.Client, alertSvc *alert.Servic
 		c.JSON(http.StatusOK, resp)
 	})
 
+	h.POST("/api/v1/alertmanager/webhook", func(_ context.Context, c *app.RequestContext) {
+		var payload alert.AlertmanagerPayload
+		if err := c.BindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, map[string]any{
+				"ok":    false,
+				"error": "invalid json body",
+			})
+			return
+		}
+
+		out, code, err := svc.HandleAlertmanagerWebhook(context.Background(), service.HandleAlertmanagerWebhookRequest{Payload: payload})
+		if err != nil {
+			c.JSON(httpStatus(code), map[string]any{
+				"ok":    false,
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, map[string]any{
+			"ok":      true,
+			"results": out.Results,
+		})
+	})
+
+	h.POST("/api/v1/silences", func(_ context.Context, c *app.RequestContext) {
+		var req alert.Silence
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, map[string]any{
+				"ok":    false,
+				"error": "invalid json body",
+			})
+			return
+		}
+
+		out, code, err := svc.CreateSilence(context.Background(), service.CreateSilenceRequest{Silence: req})
+		if err != nil {
+			c.JSON(httpStatus(code), map[string]any{
+				"ok":    false,
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, map[string]any{
+			"ok":      true,
+			"silence": out.Silence,
+		})
+	})
+
+	h.GET("/api/v1/silences", func(_ context.Context, c *app.RequestContext) {
+		out, code, err := svc.ListSilences(context.Background(), service.ListSilencesRequest{})
+		if err != nil {
+			c.JSON(httpStatus(code), map[string]any{
+				"ok":    false,
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, map[string]any{
+			"ok":    true,
+			"items": out.Items,
+		})
+	})
+
+	h.DELETE("/api/v1/silences/:id", func(_ context.Context, c *app.RequestContext) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, map[string]any{
+				"ok":    false,
+				"error": "invalid silence id",
+			})
+			return
+		}
+		out, code, err := svc.DeleteSilence(context.Background(), service.DeleteSilenceRequest{ID: id})
+		if err != nil {
+			c.JSON(httpStatus(code), map[string]any{
+				"ok":    false,
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, map[string]any{
+			"ok":      true,
+			"deleted": out.Deleted,
+		})
+	})
+
+	h.GET("/api/v1/alert/limiter/stats", func(_ context.Context, c *app.RequestContext) {
+		out, code, err := svc.GetLimiterStats(context.Background(), service.GetLimiterStatsRequest{})
+		if err != nil {
+			c.JSON(httpStatus(code), map[string]any{
+				"ok":    false,
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, map[string]any{
+			"ok":      true,
+			"buckets": out.Buckets,
+		})
+	})
+
 	h.POST("/api/v1/test/alert-burst", func(_ context.Context, c *app.RequestContext) {
 		if alertSvc == nil {
 			c.JSON(http.StatusInternalServerError, map[string]any{
@@ -173,17 +281,6 @@ func RegisterRoutes(h *server.Hertz, dt *dingtalk.Client, alertSvc *alert.Servic
 	})
 
 	h.GET("/api/v1/alerts", func(_ context.Context, c *app.RequestContext) {
-		if st == nil {
-			c.JSON(http.StatusInternalServerError, map[string]any{
-				"ok":    false,
-				"error": "store not configured",
-			})
-			return
-		}
-
-		date := string(c.Query("date"))
-		status := string(c.Query("status"))
-		group := string(c.Query("group"))
 		limit, err := parseLimit(c.Query("limit"))
 		if err != nil {
 			c.JSON(http.StatusBadRequest, map[string]any{
@@ -200,13 +297,16 @@ func RegisterRoutes(h *server.Hertz, dt *dingtalk.Client, alertSvc *alert.Servic
 			})
 			return
 		}
-		if date == "" {
-			date = chinaToday()
-		}
 
-		items, err := st.QueryAlertsByDate(date, status, group, limit, offset)
+		out, code, err := svc.QueryAlerts(context.Background(), service.QueryAlertsRequest{
+			Date:   string(c.Query("date")),
+			Status: string(c.Query("status")),
+			Group:  string(c.Query("group")),
+			Limit:  limit,
+			Offset: offset,
+		})
 		if err != nil {
-			c.JSON(http.StatusBadRequest, map[string]any{
+			c.JSON(httpStatus(code), map[string]any{
 				"ok":    false,
 				"error": err.Error(),
 			})
@@ -215,7 +315,7 @@ func RegisterRoutes(h *server.Hertz, dt *dingtalk.Client, alertSvc *alert.Servic
 
 		c.JSON(http.StatusOK, map[string]any{
 			"ok":    true,
-			"items": items,
+			"items": out.Items,
 		})
 	})
 
@@ -250,39 +350,45 @@ func RegisterRoutes(h *server.Hertz, dt *dingtalk.Client, alertSvc *alert.Servic
 	})
 
 	h.GET("/api/v1/quotes", func(_ context.Context, c *app.RequestContext) {
-		if mkt == nil {
-			c.JSON(http.StatusInternalServerError, map[string]any{
+		out, code, err := svc.GenerateQuotes(context.Background(), service.GenerateQuotesRequest{
+			RawSymbols: string(c.Query("symbols")),
+		})
+		if err != nil {
+			c.JSON(httpStatus(code), map[string]any{
 				"ok":    false,
-				"error": "market service not configured",
+				"error": err.Error(),
 			})
 			return
 		}
-		symbols := parseSymbols(string(c.Query("symbols")), defaultSymbols)
-		if len(symbols) == 0 {
-			c.JSON(http.StatusBadRequest, map[string]any{
+		c.JSON(http.StatusOK, map[string]any{
+			"ok":        true,
+			"stale":     out.Stale,
+			"source":    out.Source,
+			"source_ts": out.SourceTS,
+			"warnings":  out.Warnings,
+			"quotes":    out.Quotes,
+		})
+	})
+
+	h.GET("/api/v1/providers", func(_ context.Context, c *app.RequestContext) {
+		if mkt == nil {
+			c.JSON(http.StatusInternalServerError, map[string]any{
 				"ok":    false,
-				"error": "symbols is empty",
+				"error": "market service not configured",
 			})
 			return
 		}
-		quotes, stale, source, sourceTS, warnings, err := mkt.GetQuotesWithMeta(symbols)
-		if err != nil && len(quotes) == 0 {
-			c.JSON(http.StatusBadGateway, map[string]any{
-				"ok":    false,
-				"error": err.Error(),
+		statuses, ok := mkt.ProviderStatus()
+		if !ok {
+			c.JSON(http.StatusOK, map[string]any{
+				"ok":        true,
+				"providers": []market.ProviderStatus{},
 			})
 			return
 		}
-		if err != nil {
-			warnings = append(warnings, fmt.Sprintf("quotes fetch failed: %v", err))
-		}
 		c.JSON(http.StatusOK, map[string]any{
 			"ok":        true,
-			"stale":     stale,
-			"source":    source,
-			"source_ts": sourceTS,
-			"warnings":  warnings,
-			"quotes":    quotes,
+			"providers": statuses,
 		})
 	})
 
@@ -333,13 +439,6 @@ func RegisterRoutes(h *server.Hertz, dt *dingtalk.Client, alertSvc *alert.Servic
 	})
 
 	h.POST("/api/v1/test/snapshot", func(_ context.Context, c *app.RequestContext) {
-		if st == nil {
-			c.JSON(http.StatusInternalServerError, map[string]any{
-				"ok":    false,
-				"error": "store not configured",
-			})
-			return
-		}
 		var req struct {
 			Symbol    string  `json:"symbol"`
 			Price     float64 `json:"price"`
@@ -354,36 +453,20 @@ func RegisterRoutes(h *server.Hertz, dt *dingtalk.Client, alertSvc *alert.Servic
 			})
 			return
 		}
-		if req.Symbol == "" || req.Price <= 0 {
-			c.JSON(http.StatusBadRequest, map[string]any{
-				"ok":    false,
-				"error": "symbol and price are required",
-			})
-			return
-		}
-		if req.TS == 0 {
-			req.TS = time.Now().Unix()
-		}
-		snapshot := store.MarketSnapshot{
-			TS:        req.TS,
+
+		_, code, err := svc.GenerateSnapshot(context.Background(), service.GenerateSnapshotRequest{
 			Symbol:    req.Symbol,
 			Price:     req.Price,
 			ChangePct: req.ChangePct,
 			Volume:    req.Volume,
-		}
-		if mkt != nil {
-			mkt.IngestSnapshot(snapshot)
-		} else {
-			if err := st.InsertMarketSnapshot(snapshot); err != nil {
-				c.JSON(http.StatusBadRequest, map[string]any{
-					"ok":    false,
-					"error": err.Error(),
-				})
-				return
-			}
-			if eng != nil {
-				eng.OnSnapshot(snapshot)
-			}
+			TS:        req.TS,
+		})
+		if err != nil {
+			c.JSON(httpStatus(code), map[string]any{
+				"ok":    false,
+				"error": err.Error(),
+			})
+			return
 		}
 		c.JSON(http.StatusOK, map[string]any{
 			"ok": true,
@@ -434,13 +517,6 @@ func RegisterRoutes(h *server.Hertz, dt *dingtalk.Client, alertSvc *alert.Servic
 	})
 
 	h.POST("/api/v1/test/risk/eval", func(_ context.Context, c *app.RequestContext) {
-		if st == nil {
-			c.JSON(http.StatusInternalServerError, map[string]any{
-				"ok":    false,
-				"error": "store not configured",
-			})
-			return
-		}
 		var req struct {
 			EventID int64              `json:"event_id"`
 			Event   *store.EventRecord `json:"event"`
@@ -452,47 +528,22 @@ func RegisterRoutes(h *server.Hertz, dt *dingtalk.Client, alertSvc *alert.Servic
 			})
 			return
 		}
-		var evt *store.EventRecord
-		if req.EventID > 0 {
-			e, err := st.GetEventByID(req.EventID)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, map[string]any{
-					"ok":    false,
-					"error": err.Error(),
-				})
-				return
-			}
-			evt = e
-		} else if req.Event != nil {
-			evt = req.Event
-		} else {
-			c.JSON(http.StatusBadRequest, map[string]any{
+
+		out, code, err := svc.EvaluateRisk(context.Background(), service.EvaluateRiskRequest{
+			EventID: req.EventID,
+			Event:   req.Event,
+		})
+		if err != nil {
+			c.JSON(httpStatus(code), map[string]any{
 				"ok":    false,
-				"error": "event_id or event is required",
+				"error": err.Error(),
 			})
 			return
 		}
-		input := riskagent.EventInput{
-			EventID:  evt.ID,
-			Type:     evt.Type,
-			Severity: evt.Severity,
-			Symbol:   extractSymbolFromTitle(evt.Title),
-			Evidence: evt.EvidenceJSON,
-		}
-		applyEvidenceFields(&input, evt.EvidenceJSON)
-		decision := riskagent.FallbackDecision(input)
-		if agent != nil {
-			if d, err := agent.Evaluate(context.Background(), input); err == nil {
-				decision = d
-			} else {
-				log.Printf("risk eval error: %v", err)
-			}
-		}
-		markdown := riskagent.FormatMarkdown(evt.Title, decision)
 		c.JSON(http.StatusOK, map[string]any{
 			"ok":       true,
-			"decision": decision,
-			"markdown": markdown,
+			"decision": out.Decision,
+			"markdown": out.Markdown,
 		})
 	})
 
@@ -514,58 +565,32 @@ func RegisterRoutes(h *server.Hertz, dt *dingtalk.Client, alertSvc *alert.Servic
 	})
 
 	h.POST("/api/v1/plan/generate", func(_ context.Context, c *app.RequestContext) {
-		if st == nil || mkt == nil {
-			c.JSON(http.StatusInternalServerError, map[string]any{
-				"ok":    false,
-				"error": "store or market not configured",
-			})
-			return
-		}
-		date := string(c.Query("date"))
-		if date == "" {
-			c.JSON(http.StatusBadRequest, map[string]any{
-				"ok":    false,
-				"error": "date is required (YYYY-MM-DD)",
-			})
-			return
-		}
-		if _, err := time.Parse("2006-01-02", date); err != nil {
-			c.JSON(http.StatusBadRequest, map[string]any{
+		out, code, err := svc.GeneratePlan(context.Background(), service.GeneratePlanRequest{
+			Date:       string(c.Query("date")),
+			RawSymbols: string(c.Query("symbols")),
+		})
+		if err != nil {
+			c.JSON(httpStatus(code), map[string]any{
 				"ok":    false,
-				"error": "invalid date format (YYYY-MM-DD)",
+				"error": err.Error(),
 			})
 			return
 		}
+		c.JSON(http.StatusOK, map[string]any{
+			"ok":       true,
+			"mode":     out.Mode,
+			"plan":     out.Plan,
+			"warnings": out.Warnings,
+		})
+	})
 
-		symbols := ensureIndexSymbol(parseSymbols(string(c.Query("symbols")), defaultSymbols))
-		var warnings []string
-		quotes, stale, source, sourceTS, w, qErr := mkt.GetQuotesWithMeta(symbols)
-		warnings = append(warnings, w...)
-		if qErr != nil && len(quotes) == 0 {
-			warnings = append(warnings, fmt.Sprintf("quotes fetch failed: %v", qErr))
-		} else if stale {
-			warnings = append(warnings, fmt.Sprintf("quotes stale, source=%s source_ts=%d", source, sourceTS))
-		}
-
-		input := planagent.Input{Date: date, Quotes: quotes}
-		plan := planagent.FallbackPlan(input)
-		mode := "fallback"
-		if planAgent != nil && qErr == nil {
-			if p, err := planAgent.Evaluate(context.Background(), input); err == nil {
-				plan = p
-				mode = "llm"
-			} else {
-				log.Printf("planagent eval error: %v", err)
-				warnings = append(warnings, "planagent eval failed, fallback used")
-			}
-		}
-		contentJSON, _ := json.Marshal(plan)
-		if err := st.UpsertPlan(store.PlanRecord{
-			Date:        date,
-			ContentJSON: string(contentJSON),
-			Confirmed:   false,
-		}); err != nil {
-			c.JSON(http.StatusBadRequest, map[string]any{
+	h.POST("/api/v1/plan/debug", func(ctx context.Context, c *app.RequestContext) {
+		out, code, err := svc.DebugPlan(ctx, service.DebugPlanRequest{
+			Date:       string(c.Query("date")),
+			RawSymbols: string(c.Query("symbols")),
+		})
+		if err != nil {
+			c.JSON(httpStatus(code), map[string]any{
 				"ok":    false,
 				"error": err.Error(),
 			})
@@ -573,20 +598,12 @@ func RegisterRoutes(h *server.Hertz, dt *dingtalk.Client, alertSvc *alert.Servic
 		}
 		c.JSON(http.StatusOK, map[string]any{
 			"ok":       true,
-			"mode":     mode,
-			"plan":     plan,
-			"warnings": warnings,
+			"plan":     out.Plan,
+			"attempts": out.Attempts,
 		})
 	})
 
 	h.POST("/api/v1/plan/confirm", func(_ context.Context, c *app.RequestContext) {
-		if st == nil {
-			c.JSON(http.StatusInternalServerError, map[string]any{
-				"ok":    false,
-				"error": "store not configured",
-			})
-			return
-		}
 		var req struct {
 			Date string `json:"date"`
 		}
@@ -597,29 +614,10 @@ func RegisterRoutes(h *server.Hertz, dt *dingtalk.Client, alertSvc *alert.Servic
 			})
 			return
 		}
-		if req.Date == "" {
-			c.JSON(http.StatusBadRequest, map[string]any{
-				"ok":    false,
-				"error": "date is required (YYYY-MM-DD)",
-			})
-			return
-		}
-		if _, err := st.GetPlan(req.Date); err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				c.JSON(http.StatusBadRequest, map[string]any{
-					"ok":    false,
-					"error": "plan not found",
-				})
-				return
-			}
-			c.JSON(http.StatusBadRequest, map[string]any{
-				"ok":    false,
-				"error": err.Error(),
-			})
-			return
-		}
-		if err := st.ConfirmPlan(req.Date); err != nil {
-			c.JSON(http.StatusBadRequest, map[string]any{
+
+		_, code, err := svc.ConfirmPlan(context.Background(), service.ConfirmPlanRequest{Date: req.Date})
+		if err != nil {
+			c.JSON(httpStatus(code), map[string]any{
 				"ok":    false,
 				"error": err.Error(),
 			})
@@ -629,42 +627,52 @@ func RegisterRoutes(h *server.Hertz, dt *dingtalk.Client, alertSvc *alert.Servic
 	})
 
 	h.GET("/api/v1/plan", func(_ context.Context, c *app.RequestContext) {
-		if st == nil {
-			c.JSON(http.StatusInternalServerError, map[string]any{
+		out, code, err := svc.GetPlan(context.Background(), service.GetPlanRequest{Date: string(c.Query("date"))})
+		if err != nil {
+			c.JSON(httpStatus(code), map[string]any{
 				"ok":    false,
-				"error": "store not configured",
+				"error": err.Error(),
 			})
 			return
 		}
-		date := string(c.Query("date"))
-		if date == "" {
+		c.JSON(http.StatusOK, map[string]any{
+			"ok":        true,
+			"plan":      out.Plan,
+			"confirmed": out.Confirmed,
+		})
+	})
+
+	h.POST("/api/v1/backtest", func(ctx context.Context, c *app.RequestContext) {
+		var req struct {
+			StartDate string  `json:"start_date"`
+			EndDate   string  `json:"end_date"`
+			Symbols   string  `json:"symbols"`
+			Speed     float64 `json:"speed"`
+		}
+		if err := c.BindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, map[string]any{
 				"ok":    false,
-				"error": "date is required (YYYY-MM-DD)",
+				"error": "invalid json body",
 			})
 			return
 		}
-		rec, err := st.GetPlan(date)
+
+		out, code, err := svc.Backtest(ctx, service.BacktestRequest{
+			StartDate:  req.StartDate,
+			EndDate:    req.EndDate,
+			RawSymbols: req.Symbols,
+			Speed:      req.Speed,
+		})
 		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				c.JSON(http.StatusNotFound, map[string]any{
-					"ok":    false,
-					"error": "plan not found",
-				})
-				return
-			}
-			c.JSON(http.StatusBadRequest, map[string]any{
+			c.JSON(httpStatus(code), map[string]any{
 				"ok":    false,
 				"error": err.Error(),
 			})
 			return
 		}
-		var plan planagent.Plan
-		_ = json.Unmarshal([]byte(rec.ContentJSON), &plan)
 		c.JSON(http.StatusOK, map[string]any{
-			"ok":        true,
-			"plan":      plan,
-			"confirmed": rec.Confirmed,
+			"ok":     true,
+			"result": out.Result,
 		})
 	})
 }
@@ -743,74 +751,3 @@ func chinaToday() string {
 	}
 	return time.Now().In(loc).Format("2006-01-02")
 }
-
-func parseSymbols(raw string, defaults []string) []string {
-	raw = strings.TrimSpace(raw)
-	if raw == "" {
-		return defaults
-	}
-	parts := strings.Split(raw, ",")
-	out := make([]string, 0, len(parts))
-	for _, p := range parts {
-		p = strings.TrimSpace(p)
-		if p != "" {
-			out = append(out, p)
-		}
-	}
-	return out
-}
-
-func extractSymbolFromTitle(title string) string {
-	parts := strings.Fields(title)
-	if len(parts) > 0 {
-		return strings.ToLower(parts[0])
-	}
-	return ""
-}
-
-func ensureIndexSymbol(symbols []string) []string {
-	hasIndex := false
-	for _, s := range symbols {
-		if strings.ToLower(s) == "sh000001" {
-			hasIndex = true
-			break
-		}
-	}
-	if hasIndex {
-		return symbols
-	}
-	return append([]string{"sh000001"}, symbols...)
-}
-
-func applyEvidenceFields(input *riskagent.EventInput, evidenceJSON string) {
-	if evidenceJSON == "" {
-		return
-	}
-	var m map[string]any
-	if err := json.Unmarshal([]byte(evidenceJSON), &m); err != nil {
-		return
-	}
-	if v, ok := m["change_pct"]; ok {
-		input.ChangePct = toFloat(v)
-	}
-	if v, ok := m["drawdown_pct"]; ok {
-		input.DrawdownPct = toFloat(v)
-	}
-	if v, ok := m["window_sec"]; ok {
-		input.WindowSec = int(toFloat(v))
-	}
-}
-
-func toFloat(v any) float64 {
-	switch t := v.(type) {
-	case float64:
-		return t
-	case float32:
-		return float64(t)
-	case int:
-		return float64(t)
-	case int64:
-		return float64(t)
-	}
-	return 0
-}
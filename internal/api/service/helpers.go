@@ -0,0 +1,79 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+
+	"ai-trading-assistant/internal/riskagent"
+)
+
+func parseSymbols(raw string, defaults []string) []string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return defaults
+	}
+	parts := strings.Split(raw, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func ensureIndexSymbol(symbols []string) []string {
+	hasIndex := false
+	for _, s := range symbols {
+		if strings.ToLower(s) == "sh000001" {
+			hasIndex = true
+			break
+		}
+	}
+	if hasIndex {
+		return symbols
+	}
+	return append([]string{"sh000001"}, symbols...)
+}
+
+func extractSymbolFromTitle(title string) string {
+	parts := strings.Fields(title)
+	if len(parts) > 0 {
+		return strings.ToLower(parts[0])
+	}
+	return ""
+}
+
+func applyEvidenceFields(input *riskagent.EventInput, evidenceJSON string) {
+	if evidenceJSON == "" {
+		return
+	}
+	var m map[string]any
+	if err := json.Unmarshal([]byte(evidenceJSON), &m); err != nil {
+		return
+	}
+	if v, ok := m["change_pct"]; ok {
+		input.ChangePct = toFloat(v)
+	}
+	if v, ok := m["drawdown_pct"]; ok {
+		input.DrawdownPct = toFloat(v)
+	}
+	if v, ok := m["window_sec"]; ok {
+		input.WindowSec = int(toFloat(v))
+	}
+}
+
+func toFloat(v any) float64 {
+	switch t := v.(type) {
+	case float64:
+		return t
+	case float32:
+		return float64(t)
+	case int:
+		return float64(t)
+	case int64:
+		return float64(t)
+	}
+	return 0
+}
@@ -0,0 +1,388 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"ai-trading-assistant/internal/alert"
+	"ai-trading-assistant/internal/backtest"
+	"ai-trading-assistant/internal/engine"
+	"ai-trading-assistant/internal/market"
+	"ai-trading-assistant/internal/metrics"
+	"ai-trading-assistant/internal/planagent"
+	"ai-trading-assistant/internal/push/dingtalk"
+	"ai-trading-assistant/internal/riskagent"
+	"ai-trading-assistant/internal/store"
+)
+
+// Service implements the request/response logic shared by the HTTP and gRPC
+// entrypoints. It holds no transport state; every method takes a typed
+// request and returns a typed response plus a Status, the same shape
+// regardless of which entrypoint called it.
+type Service struct {
+	dt             *dingtalk.Client
+	alertSvc       *alert.Service
+	store          *store.Store
+	mkt            *market.Service
+	eng            *engine.Engine
+	agent          *riskagent.Agent
+	planAgent      *planagent.Agent
+	defaultSymbols []string
+}
+
+func New(dt *dingtalk.Client, alertSvc *alert.Service, st *store.Store, mkt *market.Service, eng *engine.Engine, agent *riskagent.Agent, planAgent *planagent.Agent, defaultSymbols []string) *Service {
+	return &Service{
+		dt:             dt,
+		alertSvc:       alertSvc,
+		store:          st,
+		mkt:            mkt,
+		eng:            eng,
+		agent:          agent,
+		planAgent:      planAgent,
+		defaultSymbols: defaultSymbols,
+	}
+}
+
+func (s *Service) TestPush(ctx context.Context, req TestPushRequest) (TestPushResponse, Status, error) {
+	if s.dt == nil {
+		return TestPushResponse{}, StatusUnavailable, fmt.Errorf("dingtalk client not configured")
+	}
+	resp, err := s.dt.SendMarkdown(ctx, req.Title, req.Markdown)
+	if err != nil {
+		return TestPushResponse{}, StatusUnavailable, err
+	}
+	if resp.ErrCode != 0 {
+		return TestPushResponse{DingTalkErrCode: resp.ErrCode, DingTalkErrMsg: resp.ErrMsg}, StatusUnavailable, fmt.Errorf("dingtalk returned error")
+	}
+	return TestPushResponse{DingTalkErrCode: resp.ErrCode, DingTalkErrMsg: resp.ErrMsg}, StatusOK, nil
+}
+
+func (s *Service) HandleAlert(ctx context.Context, req HandleAlertRequest) (HandleAlertResponse, Status, error) {
+	if s.alertSvc == nil {
+		return HandleAlertResponse{}, StatusUnavailable, fmt.Errorf("alert service not configured")
+	}
+	res := s.alertSvc.Handle(ctx, req.Alert)
+	return HandleAlertResponse{Result: res}, StatusOK, nil
+}
+
+// HandleAlertmanagerWebhook lets an existing Alertmanager receiver config
+// point at this service unchanged: it translates every alert in the
+// webhook payload into an alert.AlertRequest and dispatches it through the
+// same Handle path a manual /api/v1/test/alert call would use.
+func (s *Service) HandleAlertmanagerWebhook(ctx context.Context, req HandleAlertmanagerWebhookRequest) (HandleAlertmanagerWebhookResponse, Status, error) {
+	if s.alertSvc == nil {
+		return HandleAlertmanagerWebhookResponse{}, StatusUnavailable, fmt.Errorf("alert service not configured")
+	}
+	results := s.alertSvc.HandleAlertmanagerWebhook(ctx, req.Payload)
+	return HandleAlertmanagerWebhookResponse{Results: results}, StatusOK, nil
+}
+
+func (s *Service) QueryAlerts(ctx context.Context, req QueryAlertsRequest) (QueryAlertsResponse, Status, error) {
+	if s.store == nil {
+		return QueryAlertsResponse{}, StatusUnavailable, fmt.Errorf("store not configured")
+	}
+	date := req.Date
+	if date == "" {
+		date = chinaToday()
+	}
+	items, err := s.store.QueryAlertsByDate(date, req.Status, req.Group, req.Limit, req.Offset)
+	if err != nil {
+		return QueryAlertsResponse{}, StatusInternal, err
+	}
+	return QueryAlertsResponse{Items: items}, StatusOK, nil
+}
+
+func (s *Service) CreateSilence(ctx context.Context, req CreateSilenceRequest) (CreateSilenceResponse, Status, error) {
+	if s.alertSvc == nil {
+		return CreateSilenceResponse{}, StatusUnavailable, fmt.Errorf("alert service not configured")
+	}
+	if len(req.Silence.Matchers) == 0 {
+		return CreateSilenceResponse{}, StatusInvalidArgument, fmt.Errorf("silence requires at least one matcher")
+	}
+	if !req.Silence.EndsAt.After(req.Silence.StartsAt) {
+		return CreateSilenceResponse{}, StatusInvalidArgument, fmt.Errorf("ends_at must be after starts_at")
+	}
+	sil, err := s.alertSvc.CreateSilence(req.Silence)
+	if err != nil {
+		return CreateSilenceResponse{}, StatusInternal, err
+	}
+	return CreateSilenceResponse{Silence: sil}, StatusOK, nil
+}
+
+func (s *Service) ListSilences(ctx context.Context, req ListSilencesRequest) (ListSilencesResponse, Status, error) {
+	if s.alertSvc == nil {
+		return ListSilencesResponse{}, StatusUnavailable, fmt.Errorf("alert service not configured")
+	}
+	items, err := s.alertSvc.ListSilences()
+	if err != nil {
+		return ListSilencesResponse{}, StatusInternal, err
+	}
+	return ListSilencesResponse{Items: items}, StatusOK, nil
+}
+
+func (s *Service) DeleteSilence(ctx context.Context, req DeleteSilenceRequest) (DeleteSilenceResponse, Status, error) {
+	if s.alertSvc == nil {
+		return DeleteSilenceResponse{}, StatusUnavailable, fmt.Errorf("alert service not configured")
+	}
+	deleted, err := s.alertSvc.DeleteSilence(req.ID)
+	if err != nil {
+		return DeleteSilenceResponse{}, StatusInternal, err
+	}
+	if !deleted {
+		return DeleteSilenceResponse{}, StatusNotFound, fmt.Errorf("silence %d not found", req.ID)
+	}
+	return DeleteSilenceResponse{Deleted: true}, StatusOK, nil
+}
+
+func (s *Service) GetLimiterStats(ctx context.Context, req GetLimiterStatsRequest) (GetLimiterStatsResponse, Status, error) {
+	if s.alertSvc == nil {
+		return GetLimiterStatsResponse{}, StatusUnavailable, fmt.Errorf("alert service not configured")
+	}
+	return GetLimiterStatsResponse{Buckets: s.alertSvc.LimiterStats()}, StatusOK, nil
+}
+
+func (s *Service) GenerateQuotes(ctx context.Context, req GenerateQuotesRequest) (GenerateQuotesResponse, Status, error) {
+	if s.mkt == nil {
+		return GenerateQuotesResponse{}, StatusUnavailable, fmt.Errorf("market service not configured")
+	}
+	symbols := parseSymbols(req.RawSymbols, s.defaultSymbols)
+	if len(symbols) == 0 {
+		return GenerateQuotesResponse{}, StatusInvalidArgument, fmt.Errorf("symbols is empty")
+	}
+	quotes, stale, source, sourceTS, warnings, err := s.mkt.GetQuotesWithMeta(symbols)
+	if err != nil && len(quotes) == 0 {
+		return GenerateQuotesResponse{}, StatusUnavailable, err
+	}
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("quotes fetch failed: %v", err))
+	}
+	return GenerateQuotesResponse{Quotes: quotes, Stale: stale, Source: source, SourceTS: sourceTS, Warnings: warnings}, StatusOK, nil
+}
+
+func (s *Service) GenerateSnapshot(ctx context.Context, req GenerateSnapshotRequest) (GenerateSnapshotResponse, Status, error) {
+	if s.store == nil {
+		return GenerateSnapshotResponse{}, StatusUnavailable, fmt.Errorf("store not configured")
+	}
+	if req.Symbol == "" || req.Price <= 0 {
+		return GenerateSnapshotResponse{}, StatusInvalidArgument, fmt.Errorf("symbol and price are required")
+	}
+	if req.TS == 0 {
+		req.TS = time.Now().Unix()
+	}
+	snapshot := store.MarketSnapshot{
+		TS:        req.TS,
+		Symbol:    req.Symbol,
+		Price:     req.Price,
+		ChangePct: req.ChangePct,
+		Volume:    req.Volume,
+	}
+	if s.mkt != nil {
+		s.mkt.IngestSnapshot(snapshot)
+		return GenerateSnapshotResponse{}, StatusOK, nil
+	}
+	if err := s.store.InsertMarketSnapshot(snapshot); err != nil {
+		return GenerateSnapshotResponse{}, StatusInternal, err
+	}
+	if s.eng != nil {
+		s.eng.OnSnapshot(snapshot)
+	}
+	return GenerateSnapshotResponse{}, StatusOK, nil
+}
+
+func (s *Service) EvaluateRisk(ctx context.Context, req EvaluateRiskRequest) (EvaluateRiskResponse, Status, error) {
+	if s.store == nil {
+		return EvaluateRiskResponse{}, StatusUnavailable, fmt.Errorf("store not configured")
+	}
+	var evt *store.EventRecord
+	switch {
+	case req.EventID > 0:
+		e, err := s.store.GetEventByID(req.EventID)
+		if err != nil {
+			return EvaluateRiskResponse{}, StatusNotFound, err
+		}
+		evt = e
+	case req.Event != nil:
+		evt = req.Event
+	default:
+		return EvaluateRiskResponse{}, StatusInvalidArgument, fmt.Errorf("event_id or event is required")
+	}
+
+	input := riskagent.EventInput{
+		EventID:  evt.ID,
+		Type:     evt.Type,
+		Severity: evt.Severity,
+		Symbol:   extractSymbolFromTitle(evt.Title),
+		Evidence: evt.EvidenceJSON,
+	}
+	applyEvidenceFields(&input, evt.EvidenceJSON)
+	decision := riskagent.FallbackDecision(input)
+	if s.agent != nil {
+		if d, err := s.agent.Evaluate(ctx, input); err == nil {
+			decision = d
+		} else {
+			log.Printf("risk eval error: %v", err)
+		}
+	}
+	markdown := riskagent.FormatMarkdown(evt.Title, decision)
+	return EvaluateRiskResponse{Decision: decision, Markdown: markdown}, StatusOK, nil
+}
+
+func (s *Service) GeneratePlan(ctx context.Context, req GeneratePlanRequest) (GeneratePlanResponse, Status, error) {
+	if s.store == nil || s.mkt == nil {
+		return GeneratePlanResponse{}, StatusUnavailable, fmt.Errorf("store or market not configured")
+	}
+	if req.Date == "" {
+		return GeneratePlanResponse{}, StatusInvalidArgument, fmt.Errorf("date is required (YYYY-MM-DD)")
+	}
+	if _, err := time.Parse("2006-01-02", req.Date); err != nil {
+		return GeneratePlanResponse{}, StatusInvalidArgument, fmt.Errorf("invalid date format (YYYY-MM-DD)")
+	}
+
+	symbols := ensureIndexSymbol(parseSymbols(req.RawSymbols, s.defaultSymbols))
+	var warnings []string
+	quotes, stale, source, sourceTS, w, qErr := s.mkt.GetQuotesWithMeta(symbols)
+	warnings = append(warnings, w...)
+	if qErr != nil && len(quotes) == 0 {
+		warnings = append(warnings, fmt.Sprintf("quotes fetch failed: %v", qErr))
+	} else if stale {
+		warnings = append(warnings, fmt.Sprintf("quotes stale, source=%s source_ts=%d", source, sourceTS))
+	}
+
+	input := planagent.Input{Date: req.Date, Quotes: quotes}
+	plan := planagent.FallbackPlan(input, s.planAgent.Instruments())
+	mode := "fallback"
+	if s.planAgent != nil && qErr == nil {
+		if p, err := s.planAgent.Evaluate(ctx, input); err == nil {
+			plan = p
+			mode = "llm"
+		} else {
+			log.Printf("planagent eval error: %v", err)
+			warnings = append(warnings, "planagent eval failed, fallback used")
+		}
+	}
+	metrics.PlanGenerationTotal.WithLabelValues(mode).Inc()
+
+	contentJSON, _ := json.Marshal(plan)
+	if err := s.store.UpsertPlan(store.PlanRecord{
+		Date:        req.Date,
+		ContentJSON: string(contentJSON),
+		Confirmed:   false,
+	}); err != nil {
+		return GeneratePlanResponse{}, StatusInternal, err
+	}
+	return GeneratePlanResponse{Mode: mode, Plan: plan, Warnings: warnings}, StatusOK, nil
+}
+
+func (s *Service) ConfirmPlan(ctx context.Context, req ConfirmPlanRequest) (ConfirmPlanResponse, Status, error) {
+	if s.store == nil {
+		return ConfirmPlanResponse{}, StatusUnavailable, fmt.Errorf("store not configured")
+	}
+	if req.Date == "" {
+		return ConfirmPlanResponse{}, StatusInvalidArgument, fmt.Errorf("date is required (YYYY-MM-DD)")
+	}
+	if _, err := s.store.GetPlan(req.Date); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ConfirmPlanResponse{}, StatusNotFound, fmt.Errorf("plan not found")
+		}
+		return ConfirmPlanResponse{}, StatusInternal, err
+	}
+	if err := s.store.ConfirmPlan(req.Date); err != nil {
+		return ConfirmPlanResponse{}, StatusInternal, err
+	}
+	return ConfirmPlanResponse{}, StatusOK, nil
+}
+
+func (s *Service) GetPlan(ctx context.Context, req GetPlanRequest) (GetPlanResponse, Status, error) {
+	if s.store == nil {
+		return GetPlanResponse{}, StatusUnavailable, fmt.Errorf("store not configured")
+	}
+	if req.Date == "" {
+		return GetPlanResponse{}, StatusInvalidArgument, fmt.Errorf("date is required (YYYY-MM-DD)")
+	}
+	rec, err := s.store.GetPlan(req.Date)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return GetPlanResponse{}, StatusNotFound, fmt.Errorf("plan not found")
+		}
+		return GetPlanResponse{}, StatusInternal, err
+	}
+	var plan planagent.Plan
+	_ = json.Unmarshal([]byte(rec.ContentJSON), &plan)
+	return GetPlanResponse{Plan: plan, Confirmed: rec.Confirmed}, StatusOK, nil
+}
+
+// DebugPlan runs planagent's structured-output + repair loop for req.Date
+// and returns every attempt the loop made, so an operator can see why the
+// model's output was rejected without reproducing it against a live
+// prompt. Unlike GeneratePlan, it never writes to the plan table.
+func (s *Service) DebugPlan(ctx context.Context, req DebugPlanRequest) (DebugPlanResponse, Status, error) {
+	if s.store == nil || s.mkt == nil {
+		return DebugPlanResponse{}, StatusUnavailable, fmt.Errorf("store or market not configured")
+	}
+	if s.planAgent == nil {
+		return DebugPlanResponse{}, StatusUnavailable, fmt.Errorf("planagent not configured")
+	}
+	if req.Date == "" {
+		return DebugPlanResponse{}, StatusInvalidArgument, fmt.Errorf("date is required (YYYY-MM-DD)")
+	}
+	if _, err := time.Parse("2006-01-02", req.Date); err != nil {
+		return DebugPlanResponse{}, StatusInvalidArgument, fmt.Errorf("invalid date format (YYYY-MM-DD)")
+	}
+
+	symbols := ensureIndexSymbol(parseSymbols(req.RawSymbols, s.defaultSymbols))
+	quotes, _, _, _, _, err := s.mkt.GetQuotesWithMeta(symbols)
+	if err != nil && len(quotes) == 0 {
+		return DebugPlanResponse{}, StatusInternal, fmt.Errorf("quotes fetch failed: %w", err)
+	}
+
+	input := planagent.Input{Date: req.Date, Quotes: quotes}
+	plan, attempts, err := s.planAgent.EvaluateWithTrace(ctx, input)
+	if err != nil {
+		log.Printf("planagent debug eval error: %v", err)
+	}
+	return DebugPlanResponse{Plan: plan, Attempts: attempts}, StatusOK, nil
+}
+
+func (s *Service) Backtest(ctx context.Context, req BacktestRequest) (BacktestResponse, Status, error) {
+	if s.store == nil || s.eng == nil {
+		return BacktestResponse{}, StatusUnavailable, fmt.Errorf("store or engine not configured")
+	}
+	if req.StartDate == "" || req.EndDate == "" {
+		return BacktestResponse{}, StatusInvalidArgument, fmt.Errorf("start_date and end_date are required (YYYY-MM-DD)")
+	}
+	symbols := ensureIndexSymbol(parseSymbols(req.RawSymbols, s.defaultSymbols))
+
+	var live backtest.LiveStream
+	if req.Speed > 0 {
+		if s.mkt != nil {
+			live.QuoteBroker = s.mkt.Broker()
+		}
+		if s.alertSvc != nil {
+			live.AlertBroker = s.alertSvc.Broker()
+		}
+	}
+
+	res, err := backtest.Run(ctx, s.eng.Config(), s.store, s.planAgent, s.agent, live, backtest.Request{
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Symbols:   symbols,
+		Speed:     req.Speed,
+	})
+	if err != nil {
+		return BacktestResponse{}, StatusInternal, err
+	}
+	return BacktestResponse{Result: *res}, StatusOK, nil
+}
+
+func chinaToday() string {
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		return time.Now().Format("2006-01-02")
+	}
+	return time.Now().In(loc).Format("2006-01-02")
+}
@@ -0,0 +1,21 @@
+// Package service holds the transport-agnostic request/response logic shared
+// by the HTTP (internal/api) and gRPC (internal/api/grpc) entrypoints: pure
+// methods that take a typed request, talk to the existing alert/market/store/
+// agent packages, and return a typed response plus a Status. Each transport
+// is responsible for translating Status into its own status code table
+// (HTTP status codes for Hertz, grpc codes for gRPC) and for marshaling the
+// wire format; none of that belongs here.
+package service
+
+// Status is a transport-neutral outcome classification. Transports map it
+// onto their own status code space (HTTP, gRPC) via a small table instead of
+// re-deriving it from error text.
+type Status string
+
+const (
+	StatusOK              Status = "ok"
+	StatusInvalidArgument Status = "invalid_argument"
+	StatusNotFound        Status = "not_found"
+	StatusUnavailable     Status = "unavailable"
+	StatusInternal        Status = "internal"
+)
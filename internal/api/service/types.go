@@ -0,0 +1,158 @@
+package service
+
+import (
+	"ai-trading-assistant/internal/alert"
+	"ai-trading-assistant/internal/backtest"
+	"ai-trading-assistant/internal/market"
+	"ai-trading-assistant/internal/planagent"
+	"ai-trading-assistant/internal/riskagent"
+	"ai-trading-assistant/internal/store"
+)
+
+type TestPushRequest struct {
+	Title    string
+	Markdown string
+}
+
+type TestPushResponse struct {
+	DingTalkErrCode int
+	DingTalkErrMsg  string
+}
+
+type HandleAlertRequest struct {
+	Alert alert.AlertRequest
+}
+
+type HandleAlertResponse struct {
+	Result alert.Result
+}
+
+type HandleAlertmanagerWebhookRequest struct {
+	Payload alert.AlertmanagerPayload
+}
+
+type HandleAlertmanagerWebhookResponse struct {
+	Results []alert.Result
+}
+
+type QueryAlertsRequest struct {
+	Date   string
+	Status string
+	Group  string
+	Limit  int
+	Offset int
+}
+
+type QueryAlertsResponse struct {
+	Items []store.AlertRecord
+}
+
+type CreateSilenceRequest struct {
+	Silence alert.Silence
+}
+
+type CreateSilenceResponse struct {
+	Silence alert.Silence
+}
+
+type ListSilencesRequest struct{}
+
+type ListSilencesResponse struct {
+	Items []alert.Silence
+}
+
+type DeleteSilenceRequest struct {
+	ID int64
+}
+
+type DeleteSilenceResponse struct {
+	Deleted bool
+}
+
+type GetLimiterStatsRequest struct{}
+
+type GetLimiterStatsResponse struct {
+	Buckets []alert.BucketStats
+}
+
+type GenerateQuotesRequest struct {
+	RawSymbols string
+}
+
+type GenerateQuotesResponse struct {
+	Quotes   []market.Quote
+	Stale    bool
+	Source   string
+	SourceTS int64
+	Warnings []string
+}
+
+type GenerateSnapshotRequest struct {
+	Symbol    string
+	Price     float64
+	ChangePct float64
+	Volume    float64
+	TS        int64
+}
+
+type GenerateSnapshotResponse struct{}
+
+type EvaluateRiskRequest struct {
+	EventID int64
+	Event   *store.EventRecord
+}
+
+type EvaluateRiskResponse struct {
+	Decision riskagent.RiskDecision
+	Markdown string
+}
+
+type GeneratePlanRequest struct {
+	Date       string
+	RawSymbols string
+}
+
+type GeneratePlanResponse struct {
+	Mode     string
+	Plan     planagent.Plan
+	Warnings []string
+}
+
+type ConfirmPlanRequest struct {
+	Date string
+}
+
+type ConfirmPlanResponse struct{}
+
+type GetPlanRequest struct {
+	Date string
+}
+
+type GetPlanResponse struct {
+	Plan      planagent.Plan
+	Confirmed bool
+}
+
+// DebugPlanRequest/DebugPlanResponse back an operator-only endpoint that
+// runs planagent's structured-output + repair loop and returns every
+// attempt, without persisting anything to the plan table.
+type DebugPlanRequest struct {
+	Date       string
+	RawSymbols string
+}
+
+type DebugPlanResponse struct {
+	Plan     planagent.Plan
+	Attempts []planagent.Attempt
+}
+
+type BacktestRequest struct {
+	StartDate  string
+	EndDate    string
+	RawSymbols string
+	Speed      float64
+}
+
+type BacktestResponse struct {
+	Result backtest.Result
+}
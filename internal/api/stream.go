@@ -0,0 +1,265 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"ai-trading-assistant/internal/alert"
+	"ai-trading-assistant/internal/market"
+	"ai-trading-assistant/internal/store"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/protocol/http1/resp"
+	"github.com/hertz-contrib/websocket"
+)
+
+const alertBacklogSize = 20
+
+var wsUpgrader = websocket.HertzUpgrader{
+	CheckOrigin: func(_ *app.RequestContext) bool { return true },
+}
+
+// registerStreamRoutes adds the real-time counterparts of the polling
+// /api/v1/quotes and /api/v1/alerts endpoints: a WebSocket quotes feed and
+// an SSE alert feed, both backed by the market.Broker/alert.Broker pub/sub
+// layers that Service.GenerateSnapshot / Service.HandleAlert fan out to.
+func registerStreamRoutes(h *server.Hertz, alertSvc *alert.Service, st *store.Store, mkt *market.Service, defaultSymbols []string) {
+	h.GET("/api/v1/ws/quotes", func(_ context.Context, c *app.RequestContext) {
+		if mkt == nil {
+			c.JSON(http.StatusInternalServerError, map[string]any{
+				"ok":    false,
+				"error": "market service not configured",
+			})
+			return
+		}
+		filter := symbolFilter(string(c.Query("symbols")))
+
+		ch, unsubscribe := mkt.Broker().Subscribe()
+		defer unsubscribe()
+
+		if err := wsUpgrader.Upgrade(c, func(conn *websocket.Conn) {
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for {
+					if _, _, err := conn.ReadMessage(); err != nil {
+						return
+					}
+				}
+			}()
+			for {
+				select {
+				case <-done:
+					return
+				case quote, ok := <-ch:
+					if !ok {
+						return
+					}
+					if len(filter) > 0 && !filter[strings.ToLower(quote.Symbol)] {
+						continue
+					}
+					data, err := json.Marshal(quote)
+					if err != nil {
+						continue
+					}
+					if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+						return
+					}
+				}
+			}
+		}); err != nil {
+			log.Printf("ws quotes upgrade error: %v", err)
+		}
+	})
+
+	h.GET("/api/v1/stream/alerts", func(ctx context.Context, c *app.RequestContext) {
+		if alertSvc == nil {
+			c.JSON(http.StatusInternalServerError, map[string]any{
+				"ok":    false,
+				"error": "alert service not configured",
+			})
+			return
+		}
+		groupFilter := string(c.Query("group"))
+		statusFilter := string(c.Query("status"))
+		priorityFilter := string(c.Query("priority"))
+		matches := func(rec store.AlertRecord) bool {
+			return priorityFilter == "" || rec.Priority == priorityFilter
+		}
+
+		c.SetStatusCode(http.StatusOK)
+		c.Response.Header.Set("Content-Type", "text/event-stream")
+		c.Response.Header.Set("Cache-Control", "no-cache")
+		c.Response.Header.Set("Connection", "keep-alive")
+		c.Response.HijackWriter(resp.NewChunkedBodyWriter(&c.Response, c.GetWriter()))
+
+		ch, unsubscribe := alertSvc.Broker().Subscribe()
+		defer unsubscribe()
+
+		if st != nil {
+			backlog, err := st.QueryAlertsByDate(chinaToday(), statusFilter, groupFilter, alertBacklogSize, 0)
+			if err != nil {
+				log.Printf("alert backlog query error: %v", err)
+			}
+			for _, rec := range backlog {
+				if !matches(rec) {
+					continue
+				}
+				writeAlertEvent(c, rec)
+			}
+			c.Flush()
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case rec, ok := <-ch:
+				if !ok {
+					return
+				}
+				if groupFilter != "" && rec.GroupName != groupFilter {
+					continue
+				}
+				if statusFilter != "" && rec.Status != statusFilter {
+					continue
+				}
+				if !matches(rec) {
+					continue
+				}
+				writeAlertEvent(c, rec)
+				c.Flush()
+			}
+		}
+	})
+
+	// POST /api/backtest spawns a market.Service.ReplayRange against a
+	// stored date range and streams each replayed quote back as an SSE
+	// progress event, rather than blocking until the whole range has
+	// replayed (the synchronous aggregate-stats replay lives at
+	// POST /api/v1/backtest).
+	h.POST("/api/backtest", func(ctx context.Context, c *app.RequestContext) {
+		if mkt == nil {
+			c.JSON(http.StatusInternalServerError, map[string]any{
+				"ok":    false,
+				"error": "market service not configured",
+			})
+			return
+		}
+		var req struct {
+			StartDate string  `json:"start_date"`
+			EndDate   string  `json:"end_date"`
+			Symbols   string  `json:"symbols"`
+			Speed     float64 `json:"speed"`
+		}
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, map[string]any{
+				"ok":    false,
+				"error": "invalid json body",
+			})
+			return
+		}
+		loc, err := time.LoadLocation("Asia/Shanghai")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, map[string]any{"ok": false, "error": err.Error()})
+			return
+		}
+		from, err := time.ParseInLocation("2006-01-02", req.StartDate, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, map[string]any{"ok": false, "error": "invalid start_date"})
+			return
+		}
+		to, err := time.ParseInLocation("2006-01-02", req.EndDate, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, map[string]any{"ok": false, "error": "invalid end_date"})
+			return
+		}
+		symbols := parseReplaySymbols(req.Symbols, defaultSymbols)
+
+		c.SetStatusCode(http.StatusOK)
+		c.Response.Header.Set("Content-Type", "text/event-stream")
+		c.Response.Header.Set("Cache-Control", "no-cache")
+		c.Response.Header.Set("Connection", "keep-alive")
+		c.Response.HijackWriter(resp.NewChunkedBodyWriter(&c.Response, c.GetWriter()))
+
+		ch, unsubscribe := mkt.Broker().Subscribe()
+		defer unsubscribe()
+
+		done := make(chan error, 1)
+		go func() { done <- mkt.ReplayRange(ctx, symbols, from, to, req.Speed) }()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case quote := <-ch:
+				writeBacktestEvent(c, "progress", quote)
+				c.Flush()
+			case err := <-done:
+				if err != nil {
+					writeBacktestEvent(c, "error", map[string]string{"error": err.Error()})
+				} else {
+					writeBacktestEvent(c, "done", map[string]bool{"ok": true})
+				}
+				c.Flush()
+				return
+			}
+		}
+	})
+}
+
+func writeBacktestEvent(c *app.RequestContext, event string, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	c.Write([]byte("event: " + event + "\n"))
+	c.Write(append(append([]byte("data: "), data...), '\n', '\n'))
+}
+
+// parseReplaySymbols parses a comma-separated "symbols" field, falling back
+// to defaultSymbols when raw is empty.
+func parseReplaySymbols(raw string, defaultSymbols []string) []string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return defaultSymbols
+	}
+	var out []string
+	for _, p := range strings.Split(raw, ",") {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func writeAlertEvent(c *app.RequestContext, rec store.AlertRecord) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	c.Write(append(append([]byte("data: "), data...), '\n', '\n'))
+}
+
+// symbolFilter parses a comma-separated "symbols" query param into a
+// lowercase lookup set. An empty raw value yields an empty (unfiltered) set.
+func symbolFilter(raw string) map[string]bool {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	out := make(map[string]bool)
+	for _, p := range strings.Split(raw, ",") {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			out[strings.ToLower(p)] = true
+		}
+	}
+	return out
+}
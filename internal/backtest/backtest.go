@@ -0,0 +1,338 @@
+// Package backtest replays stored market snapshots through a fresh engine
+// and risk/plan pipeline to produce aggregate statistics (events produced,
+// alerts by priority/status, per-rule trigger counts, hypothetical plan
+// PnL) without touching the live alert/notification state. Alerting is
+// redirected to an in-memory recorder that satisfies engine.AlertSink, and
+// event/risk bookkeeping goes to a throwaway sqlite store removed when the
+// run finishes.
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"ai-trading-assistant/internal/alert"
+	"ai-trading-assistant/internal/engine"
+	"ai-trading-assistant/internal/market"
+	"ai-trading-assistant/internal/planagent"
+	"ai-trading-assistant/internal/riskagent"
+	"ai-trading-assistant/internal/store"
+)
+
+// maxReplaySleep bounds the pause between two consecutive snapshots during
+// a paced (Speed > 0) replay, so an overnight or weekend gap in the
+// historical data doesn't stall the run for hours.
+const maxReplaySleep = 5 * time.Second
+
+// Request describes one backtest run.
+type Request struct {
+	StartDate string
+	EndDate   string
+	Symbols   []string
+	// Speed paces the replay against the snapshots' original timestamps
+	// (1.0 = real time, 10.0 = 10x real time). Zero or negative means
+	// replay as fast as possible with no pacing and no live streaming.
+	Speed float64
+}
+
+// PlanOutcome is one day's generated plan plus its hypothetical PnL against
+// the next available trading day's open.
+type PlanOutcome struct {
+	Date   string
+	Mode   string
+	Plan   planagent.Plan
+	PnLPct float64
+}
+
+// Result aggregates the outcome of a backtest run.
+type Result struct {
+	StartDate         string
+	EndDate           string
+	Symbols           []string
+	EventsProduced    int
+	AlertsByPriority  map[string]int
+	AlertsByStatus    map[string]int
+	RuleTriggerCounts map[string]int
+	Plans             []PlanOutcome
+}
+
+// LiveStream optionally mirrors the replay onto the live streaming
+// endpoints (GET /api/v1/ws/quotes, GET /api/v1/stream/alerts) so a
+// dashboard can watch a historical day play out. Only used when
+// Request.Speed > 0.
+type LiveStream struct {
+	QuoteBroker *market.Broker
+	AlertBroker *alert.Broker
+}
+
+// Run replays every snapshot for req.Symbols between req.StartDate and
+// req.EndDate (inclusive, Asia/Shanghai calendar days) in chronological
+// order through a freshly constructed engine.
+func Run(ctx context.Context, cfg engine.Config, liveStore *store.Store, planAgent *planagent.Agent, agent *riskagent.Agent, live LiveStream, req Request) (*Result, error) {
+	if liveStore == nil {
+		return nil, fmt.Errorf("store not configured")
+	}
+	if req.StartDate == "" || req.EndDate == "" {
+		return nil, fmt.Errorf("start_date and end_date are required (YYYY-MM-DD)")
+	}
+	if len(req.Symbols) == 0 {
+		return nil, fmt.Errorf("symbols is empty")
+	}
+
+	bySymbol := make(map[string][]store.MarketSnapshot, len(req.Symbols))
+	var feed []store.MarketSnapshot
+	for _, sym := range req.Symbols {
+		snaps, err := liveStore.QueryMarketSnapshotsRange(sym, req.StartDate, req.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("query snapshots for %s: %w", sym, err)
+		}
+		bySymbol[sym] = snaps
+		feed = append(feed, snaps...)
+	}
+	sort.SliceStable(feed, func(i, j int) bool { return feed[i].TS < feed[j].TS })
+
+	result := &Result{
+		StartDate:         req.StartDate,
+		EndDate:           req.EndDate,
+		Symbols:           req.Symbols,
+		AlertsByPriority:  map[string]int{},
+		AlertsByStatus:    map[string]int{},
+		RuleTriggerCounts: map[string]int{},
+	}
+	if len(feed) == 0 {
+		return result, nil
+	}
+
+	bktStore, cleanup, err := openScratchStore()
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	var alertBroker *alert.Broker
+	if req.Speed > 0 {
+		alertBroker = live.AlertBroker
+	}
+	sink := newAlertRecorder(alertBroker)
+	eng := engine.New(cfg, bktStore, sink, agent, nil)
+
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		return nil, fmt.Errorf("load tz: %w", err)
+	}
+	dateOf := func(ts int64) string { return time.Unix(ts, 0).In(loc).Format("2006-01-02") }
+
+	var plans []PlanOutcome
+	lastSeen := make(map[string]store.MarketSnapshot)
+	currentDate := dateOf(feed[0].TS)
+	var prevTS int64
+
+	flushDay := func(date string) {
+		plan, mode := generatePlan(ctx, planAgent, date, lastSeen)
+		if len(plan.TradePool) == 0 {
+			return
+		}
+		plans = append(plans, PlanOutcome{
+			Date:   date,
+			Mode:   mode,
+			Plan:   plan,
+			PnLPct: scorePlan(plan, date, lastSeen, bySymbol, dateOf),
+		})
+	}
+
+	for i, snap := range feed {
+		d := dateOf(snap.TS)
+		if d != currentDate {
+			flushDay(currentDate)
+			currentDate = d
+		}
+		if req.Speed > 0 && i > 0 {
+			if err := pace(ctx, prevTS, snap.TS, req.Speed); err != nil {
+				return nil, err
+			}
+		}
+		prevTS = snap.TS
+		lastSeen[snap.Symbol] = snap
+		eng.OnSnapshot(snap)
+		if req.Speed > 0 && live.QuoteBroker != nil {
+			live.QuoteBroker.Publish(market.Quote{
+				Symbol:    snap.Symbol,
+				Price:     snap.Price,
+				ChangePct: snap.ChangePct,
+				Volume:    snap.Volume,
+				TS:        snap.TS,
+			})
+		}
+	}
+	flushDay(currentDate)
+	result.Plans = plans
+
+	ruleTriggers, err := bktStore.CountEventsByType(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("count events by type: %w", err)
+	}
+	result.RuleTriggerCounts = ruleTriggers
+	for _, n := range ruleTriggers {
+		result.EventsProduced += n
+	}
+
+	byPriority, byStatus := sink.snapshot()
+	result.AlertsByPriority = byPriority
+	result.AlertsByStatus = byStatus
+	return result, nil
+}
+
+func generatePlan(ctx context.Context, planAgent *planagent.Agent, date string, lastSeen map[string]store.MarketSnapshot) (planagent.Plan, string) {
+	quotes := make([]market.Quote, 0, len(lastSeen))
+	for _, s := range lastSeen {
+		quotes = append(quotes, market.Quote{
+			Symbol:    s.Symbol,
+			Price:     s.Price,
+			ChangePct: s.ChangePct,
+			Volume:    s.Volume,
+			TS:        s.TS,
+		})
+	}
+	sort.Slice(quotes, func(i, j int) bool { return quotes[i].Symbol < quotes[j].Symbol })
+
+	input := planagent.Input{Date: date, Quotes: quotes}
+	plan := planagent.FallbackPlan(input, planAgent.Instruments())
+	mode := "fallback"
+	if planAgent != nil {
+		if p, err := planAgent.Evaluate(ctx, input); err == nil {
+			plan = p
+			mode = "llm"
+		}
+	}
+	return plan, mode
+}
+
+// scorePlan weights each trade's return (day-close entry vs the next
+// available trading day's open) by its PositionPct and averages the
+// result. Trades in symbols with no day-close or next-open data are
+// skipped rather than scored as zero.
+func scorePlan(plan planagent.Plan, date string, lastSeen map[string]store.MarketSnapshot, bySymbol map[string][]store.MarketSnapshot, dateOf func(int64) string) float64 {
+	var weighted, totalWeight float64
+	for _, t := range plan.TradePool {
+		entry, ok := lastSeen[t.Symbol]
+		if !ok || entry.Price <= 0 {
+			continue
+		}
+		var nextOpen float64
+		for _, s := range bySymbol[t.Symbol] {
+			if s.TS > entry.TS && dateOf(s.TS) != date {
+				nextOpen = s.Price
+				break
+			}
+		}
+		if nextOpen <= 0 {
+			continue
+		}
+		weight := t.PositionPct
+		if weight <= 0 {
+			weight = 1
+		}
+		retPct := (nextOpen - entry.Price) / entry.Price * 100
+		weighted += retPct * weight
+		totalWeight += weight
+	}
+	if totalWeight == 0 {
+		return 0
+	}
+	return weighted / totalWeight
+}
+
+func pace(ctx context.Context, prevTS, ts int64, speed float64) error {
+	gap := time.Duration(ts-prevTS) * time.Second
+	if gap <= 0 {
+		return nil
+	}
+	sleepFor := time.Duration(float64(gap) / speed)
+	if sleepFor > maxReplaySleep {
+		sleepFor = maxReplaySleep
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(sleepFor):
+		return nil
+	}
+}
+
+func openScratchStore() (*store.Store, func(), error) {
+	path := filepath.Join(os.TempDir(), fmt.Sprintf("backtest-%d-%d.db", os.Getpid(), time.Now().UnixNano()))
+	st, err := store.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open backtest store: %w", err)
+	}
+	cleanup := func() {
+		_ = st.Close()
+		_ = os.Remove(path)
+		_ = os.Remove(path + "-wal")
+		_ = os.Remove(path + "-shm")
+	}
+	return st, cleanup, nil
+}
+
+// alertRecorder is an in-memory engine.AlertSink used in place of
+// *alert.Service during a backtest: it records outcome counts instead of
+// sending anything, and optionally mirrors each alert onto the live
+// alert.Broker so an SSE client watching a paced replay sees it.
+type alertRecorder struct {
+	mu         sync.Mutex
+	byPriority map[string]int
+	byStatus   map[string]int
+	liveBroker *alert.Broker
+}
+
+func newAlertRecorder(liveBroker *alert.Broker) *alertRecorder {
+	return &alertRecorder{
+		byPriority: make(map[string]int),
+		byStatus:   make(map[string]int),
+		liveBroker: liveBroker,
+	}
+}
+
+func (r *alertRecorder) Handle(_ context.Context, req alert.AlertRequest) alert.Result {
+	res := alert.Result{Status: alert.StatusSent}
+
+	r.mu.Lock()
+	r.byPriority[string(req.Priority)]++
+	r.byStatus[string(res.Status)]++
+	r.mu.Unlock()
+
+	if r.liveBroker != nil {
+		now := time.Now()
+		r.liveBroker.Publish(store.AlertRecord{
+			TS:        now.Unix(),
+			Priority:  string(req.Priority),
+			GroupName: req.Group,
+			Title:     req.Title,
+			DedupKey:  req.DedupKey,
+			MergeKey:  req.MergeKey,
+			Status:    string(res.Status),
+			Channel:   "backtest",
+			CreatedAt: now.Format(time.RFC3339),
+		})
+	}
+	return res
+}
+
+func (r *alertRecorder) snapshot() (map[string]int, map[string]int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	byPriority := make(map[string]int, len(r.byPriority))
+	for k, v := range r.byPriority {
+		byPriority[k] = v
+	}
+	byStatus := make(map[string]int, len(r.byStatus))
+	for k, v := range r.byStatus {
+		byStatus[k] = v
+	}
+	return byPriority, byStatus
+}
@@ -9,21 +9,28 @@ import (
 )
 
 type Config struct {
-	Server    ServerConfig    `yaml:"server"`
-	Log       LogConfig       `yaml:"log"`
-	Push      PushConfig      `yaml:"push"`
-	Alert     AlertConfig     `yaml:"alert"`
-	Store     StoreConfig     `yaml:"store"`
-	Market    MarketConfig    `yaml:"market"`
-	Engine    EngineConfig    `yaml:"engine"`
-	RiskAgent RiskAgentConfig `yaml:"risk_agent"`
-	PlanAgent PlanAgentConfig `yaml:"plan_agent"`
+	Server        ServerConfig        `yaml:"server"`
+	GRPC          GRPCConfig          `yaml:"grpc"`
+	Log           LogConfig           `yaml:"log"`
+	Push          PushConfig          `yaml:"push"`
+	Alert         AlertConfig         `yaml:"alert"`
+	Store         StoreConfig         `yaml:"store"`
+	Market        MarketConfig        `yaml:"market"`
+	Engine        EngineConfig        `yaml:"engine"`
+	RiskAgent     RiskAgentConfig     `yaml:"risk_agent"`
+	PlanAgent     PlanAgentConfig     `yaml:"plan_agent"`
+	Notifications NotificationsConfig `yaml:"notifications"`
 }
 
 type ServerConfig struct {
 	Port int `yaml:"port"`
 }
 
+type GRPCConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Port    int  `yaml:"port"`
+}
+
 type LogConfig struct {
 	Level string `yaml:"level"`
 }
@@ -39,15 +46,99 @@ type DingtalkConfig struct {
 }
 
 type AlertConfig struct {
-	RateLimit RateLimitConfig `yaml:"rate_limit"`
-	Dedup     DedupConfig     `yaml:"dedup"`
-	Merge     MergeConfig     `yaml:"merge"`
-	Digest    DigestConfig    `yaml:"digest"`
+	RateLimit RateLimitConfig     `yaml:"rate_limit"`
+	Dedup     DedupConfig         `yaml:"dedup"`
+	Merge     MergeConfig         `yaml:"merge"`
+	Digest    DigestConfig        `yaml:"digest"`
+	Channels  AlertChannelsConfig `yaml:"channels"`
+
+	// Routing maps an alert group name to the channel names (keys of
+	// Channels, plus the always-available "dingtalk") that should receive
+	// it. A group with no entry falls back to DefaultChannels.
+	Routing         map[string][]string `yaml:"routing"`
+	DefaultChannels []string            `yaml:"default_channels"`
+
+	Cluster AlertClusterConfig `yaml:"cluster"`
+}
+
+// AlertClusterConfig enables the memberlist-based ClusterCoordinator so
+// multiple alert.Service replicas behind a load balancer share dedup
+// ownership and divide up RateLimit instead of each enforcing it alone.
+// Enabled defaults to false, which keeps a single-replica deployment on
+// alert.NoopCoordinator unchanged.
+type AlertClusterConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	NodeName string   `yaml:"node_name"`
+	BindAddr string   `yaml:"bind_addr"`
+	BindPort int      `yaml:"bind_port"`
+	Seeds    []string `yaml:"seeds"`
+}
+
+// AlertChannelsConfig configures the non-DingTalk channels alert.Service can
+// fan a decision out to. DingTalk itself is configured via PushConfig.Dingtalk
+// since it predates this multi-channel registry and is always wired in.
+type AlertChannelsConfig struct {
+	Lark    AlertLarkConfig    `yaml:"lark"`
+	Slack   AlertSlackConfig   `yaml:"slack"`
+	Webhook AlertWebhookConfig `yaml:"webhook"`
+	SMTP    AlertSMTPConfig    `yaml:"smtp"`
+}
+
+type AlertLarkConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Webhook   string `yaml:"webhook"`
+	Secret    string `yaml:"secret"`
+	TimeoutMs int    `yaml:"timeout_ms"`
+	// MinSeverity gates delivery to this channel: "low" (default, no
+	// filtering), "med", or "high".
+	MinSeverity string `yaml:"min_severity"`
+}
+
+type AlertSlackConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	WebhookURL  string `yaml:"webhook_url"`
+	TimeoutMs   int    `yaml:"timeout_ms"`
+	MinSeverity string `yaml:"min_severity"`
+}
+
+type AlertWebhookConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	URL         string `yaml:"url"`
+	Secret      string `yaml:"secret"`
+	TimeoutMs   int    `yaml:"timeout_ms"`
+	MinSeverity string `yaml:"min_severity"`
+}
+
+type AlertSMTPConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
 }
 
 type RateLimitConfig struct {
 	PerMinute int `yaml:"per_minute"`
 	Burst     int `yaml:"burst"`
+
+	// GroupWeights gives each alert group a share of PerMinute/Burst
+	// relative to other groups; a group with no entry gets weight 1.
+	GroupWeights map[string]int `yaml:"group_weights"`
+
+	// LowReservationPct is the fraction of a group's Scheduler capacity
+	// set aside for low-priority draws. alert.Service queues low-priority
+	// alerts to the digest without drawing from the Scheduler, so there
+	// only high-priority borrowing (HighBorrowCeilingPct) uses it.
+	// Defaults to 0.2 if unset.
+	LowReservationPct float64 `yaml:"low_reservation_pct"`
+
+	// HighBorrowCeilingPct caps how much of the low-priority reservation a
+	// high-priority alert may borrow once a group's general pool is
+	// exhausted, as a fraction of that reservation. Defaults to 0.5 if
+	// unset.
+	HighBorrowCeilingPct float64 `yaml:"high_borrow_ceiling_pct"`
 }
 
 type DedupConfig struct {
@@ -63,24 +154,101 @@ type DigestConfig struct {
 }
 
 type StoreConfig struct {
+	// DSN, when set, overrides Sqlite.Path and is passed to store.Open
+	// as-is (e.g. "postgres://user:pw@host/db"). Leave empty to keep
+	// using the local SQLite file at Sqlite.Path.
+	DSN    string       `yaml:"dsn"`
 	Sqlite SqliteConfig `yaml:"sqlite"`
 }
 
+// ResolveDSN returns the DSN store.Open should use: DSN if set, otherwise
+// Sqlite.Path for backward compatibility with configs predating the
+// pluggable-backend DSN field.
+func (c StoreConfig) ResolveDSN() string {
+	if c.DSN != "" {
+		return c.DSN
+	}
+	return c.Sqlite.Path
+}
+
 type SqliteConfig struct {
 	Path string `yaml:"path"`
 }
 
 type MarketConfig struct {
-	Symbols              []string `yaml:"symbols"`
-	PollIntervalSec      int      `yaml:"poll_interval_sec"`
-	MinRequestIntervalMs int      `yaml:"min_request_interval_ms"`
+	Symbols              []string                    `yaml:"symbols"`
+	PollIntervalSec      int                         `yaml:"poll_interval_sec"`
+	MinRequestIntervalMs int                         `yaml:"min_request_interval_ms"`
+	RateLimitPerSec      float64                     `yaml:"rate_limit_per_sec"`
+	RateLimitBurst       int                         `yaml:"rate_limit_burst"`
+	GenericProviders     []GenericRestProviderConfig `yaml:"generic_providers"`
+
+	// Providers, when non-empty, replaces the built-in default provider
+	// set (eastmoney, sina, tencent) with an explicit, ordered list —
+	// letting an operator add tushare (which needs a Token) or disable/
+	// reorder the free feeds. GenericProviders are appended after these
+	// regardless, since they're a separate (URL-template) mechanism.
+	Providers []ProviderSpecConfig `yaml:"providers"`
+
+	// Strategy selects MultiProvider's fan-out mode: "failover" (default),
+	// "race", "quorum", or "hedged".
+	Strategy string `yaml:"strategy"`
+	// TieBreaker selects how StrategyQuorum picks a winner when providers
+	// disagree: "consensus" (default, closest to median) or "freshest"
+	// (largest TS).
+	TieBreaker string `yaml:"tie_breaker"`
+
+	// Mode selects how live quotes reach the engine: "poll" (default,
+	// PollLoop on Providers/GenericProviders), "stream" (push-based via
+	// StreamProviders only, no HTTP polling), or "hybrid" (stream-first,
+	// falling back to polling the same MultiProvider for any symbol whose
+	// streams have gone stale).
+	Mode string `yaml:"mode"`
+	// StreamProviders names the built-in StreamingProviders to subscribe
+	// when Mode is "stream" or "hybrid": one of "binance-ws",
+	// "binance-futures-ws", "okx-ws", "okx-futures-ws".
+	StreamProviders []string           `yaml:"stream_providers"`
+	Stream          MarketStreamConfig `yaml:"stream"`
+}
+
+// MarketStreamConfig tunes MultiStreamProvider when Mode is "stream" or
+// "hybrid". Zero values fall back to MultiStreamProvider's own defaults.
+type MarketStreamConfig struct {
+	RingSize        int `yaml:"ring_size"`
+	ReconnectBaseMs int `yaml:"reconnect_base_ms"`
+	ReconnectMaxMs  int `yaml:"reconnect_max_ms"`
+	FallbackPollSec int `yaml:"fallback_poll_sec"`
+	StaleAfterSec   int `yaml:"stale_after_sec"`
+}
+
+// ProviderSpecConfig names one built-in market data provider to enable, in
+// the order it should be tried/fanned-out. Name is one of "eastmoney",
+// "sina", "tencent", "tushare". Token is only meaningful for providers that
+// require a credential (currently just tushare); it's ignored otherwise.
+type ProviderSpecConfig struct {
+	Name      string `yaml:"name"`
+	Enabled   bool   `yaml:"enabled"`
+	Token     string `yaml:"token"`
+	TimeoutMs int    `yaml:"timeout_ms"`
+}
+
+type GenericRestProviderConfig struct {
+	Name          string `yaml:"name"`
+	URLTemplate   string `yaml:"url_template"`
+	PricePath     string `yaml:"price_path"`
+	ChangePctPath string `yaml:"change_pct_path"`
+	VolumePath    string `yaml:"volume_path"`
+	NamePath      string `yaml:"name_path"`
+	TimeoutMs     int    `yaml:"timeout_ms"`
 }
 
 type EngineConfig struct {
 	IndexRisk     EngineIndexRiskConfig    `yaml:"index_risk"`
 	PanicDrop     EnginePanicDropConfig    `yaml:"panic_drop"`
+	VolAdj        EngineVolAdjConfig       `yaml:"vol_adj"`
 	VolumeSpike   EngineVolumeSpikeConfig  `yaml:"volume_spike"`
 	KeyBreakDown  EngineKeyBreakDownConfig `yaml:"key_break_down"`
+	NarrowRange   EngineNarrowRangeConfig  `yaml:"narrow_range"`
 	WindowMaxKeep int                      `yaml:"window_max_keep"`
 	CooldownSec   EngineCooldownConfig     `yaml:"cooldown_sec"`
 }
@@ -97,6 +265,12 @@ type EnginePanicDropConfig struct {
 	HighPct   float64 `yaml:"high_pct"`
 }
 
+type EngineVolAdjConfig struct {
+	ZWindowPoints int     `yaml:"z_window_points"`
+	MedSigma      float64 `yaml:"med_sigma"`
+	HighSigma     float64 `yaml:"high_sigma"`
+}
+
 type EngineVolumeSpikeConfig struct {
 	MaPoints int     `yaml:"ma_points"`
 	Ratio    float64 `yaml:"ratio"`
@@ -107,11 +281,21 @@ type EngineKeyBreakDownConfig struct {
 	Priority string             `yaml:"priority"`
 }
 
+type EngineNarrowRangeConfig struct {
+	N            int     `yaml:"n"`
+	BarPeriodSec int64   `yaml:"bar_period_sec"`
+	AtrPeriod    int     `yaml:"atr_period"`
+	AvgRangeN    int     `yaml:"avg_range_n"`
+	HighAtrPct   float64 `yaml:"high_atr_pct"`
+}
+
 type EngineCooldownConfig struct {
 	IndexRisk    int `yaml:"index_risk"`
 	PanicDrop    int `yaml:"panic_drop"`
+	VolAdj       int `yaml:"vol_adj"`
 	VolumeSpike  int `yaml:"volume_spike"`
 	KeyBreakDown int `yaml:"key_break_down"`
+	NarrowRange  int `yaml:"narrow_range"`
 }
 
 type RiskAgentConfig struct {
@@ -125,13 +309,59 @@ type RiskAgentConfig struct {
 }
 
 type PlanAgentConfig struct {
-	Enabled    bool   `yaml:"enabled"`
-	Model      string `yaml:"model"`
-	APIKey     string `yaml:"api_key"`
-	BaseURL    string `yaml:"base_url"`
-	ByAzure    bool   `yaml:"by_azure"`
-	APIVersion string `yaml:"api_version"`
-	TimeoutMs  int    `yaml:"timeout_ms"`
+	Enabled           bool   `yaml:"enabled"`
+	Model             string `yaml:"model"`
+	APIKey            string `yaml:"api_key"`
+	BaseURL           string `yaml:"base_url"`
+	ByAzure           bool   `yaml:"by_azure"`
+	APIVersion        string `yaml:"api_version"`
+	TimeoutMs         int    `yaml:"timeout_ms"`
+	MaxRepairAttempts int    `yaml:"max_repair_attempts"`
+}
+
+type NotificationsConfig struct {
+	Lark           LarkConfig     `yaml:"lark"`
+	Webhook        WebhookConfig  `yaml:"webhook"`
+	Telegram       TelegramConfig `yaml:"telegram"`
+	Slack          SlackConfig    `yaml:"slack"`
+	DedupWindowSec int            `yaml:"dedup_window_sec"`
+	MinIntervalSec int            `yaml:"min_interval_sec"`
+	// MaxRetries/RetryBackoffMs tune the per-channel retry policy shared by
+	// every enabled notifier.
+	MaxRetries     int `yaml:"max_retries"`
+	RetryBackoffMs int `yaml:"retry_backoff_ms"`
+}
+
+type LarkConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Webhook   string `yaml:"webhook"`
+	Secret    string `yaml:"secret"`
+	TimeoutMs int    `yaml:"timeout_ms"`
+	// MinSeverity gates delivery to this channel: "low" (default, no
+	// filtering), "med", or "high".
+	MinSeverity string `yaml:"min_severity"`
+}
+
+type WebhookConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	URL         string `yaml:"url"`
+	TimeoutMs   int    `yaml:"timeout_ms"`
+	MinSeverity string `yaml:"min_severity"`
+}
+
+type TelegramConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	BotToken    string `yaml:"bot_token"`
+	ChatID      string `yaml:"chat_id"`
+	TimeoutMs   int    `yaml:"timeout_ms"`
+	MinSeverity string `yaml:"min_severity"`
+}
+
+type SlackConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	WebhookURL  string `yaml:"webhook_url"`
+	TimeoutMs   int    `yaml:"timeout_ms"`
+	MinSeverity string `yaml:"min_severity"`
 }
 
 func Load(path string) (*Config, error) {
@@ -142,15 +372,17 @@ func Load(path string) (*Config, error) {
 
 	cfg := Config{
 		Server: ServerConfig{Port: 8080},
+		GRPC:   GRPCConfig{Enabled: false, Port: 9090},
 		Log:    LogConfig{Level: "info"},
 		Push: PushConfig{
 			Dingtalk: DingtalkConfig{TimeoutMs: 5000},
 		},
 		Alert: AlertConfig{
-			RateLimit: RateLimitConfig{PerMinute: 60, Burst: 10},
-			Dedup:     DedupConfig{WindowSec: 60},
-			Merge:     MergeConfig{WindowSec: 30},
-			Digest:    DigestConfig{LowIntervalSec: 60},
+			RateLimit:       RateLimitConfig{PerMinute: 60, Burst: 10, LowReservationPct: 0.2, HighBorrowCeilingPct: 0.5},
+			Dedup:           DedupConfig{WindowSec: 60},
+			Merge:           MergeConfig{WindowSec: 30},
+			Digest:          DigestConfig{LowIntervalSec: 60},
+			DefaultChannels: []string{"dingtalk"},
 		},
 		Store: StoreConfig{
 			Sqlite: SqliteConfig{Path: "data/app.db"},
@@ -159,6 +391,8 @@ func Load(path string) (*Config, error) {
 			Symbols:              []string{"sh000001", "sh600000", "sz000001"},
 			PollIntervalSec:      30,
 			MinRequestIntervalMs: 1000,
+			RateLimitPerSec:      5,
+			RateLimitBurst:       5,
 		},
 		Engine: EngineConfig{
 			IndexRisk: EngineIndexRiskConfig{
@@ -171,6 +405,11 @@ func Load(path string) (*Config, error) {
 				MedPct:    2.0,
 				HighPct:   4.0,
 			},
+			VolAdj: EngineVolAdjConfig{
+				ZWindowPoints: 60,
+				MedSigma:      2.0,
+				HighSigma:     3.0,
+			},
 			VolumeSpike: EngineVolumeSpikeConfig{
 				MaPoints: 5,
 				Ratio:    3.0,
@@ -181,12 +420,21 @@ func Load(path string) (*Config, error) {
 				},
 				Priority: "med",
 			},
+			NarrowRange: EngineNarrowRangeConfig{
+				N:            4,
+				BarPeriodSec: 300,
+				AtrPeriod:    14,
+				AvgRangeN:    20,
+				HighAtrPct:   1.0,
+			},
 			WindowMaxKeep: 200,
 			CooldownSec: EngineCooldownConfig{
 				IndexRisk:    300,
 				PanicDrop:    180,
+				VolAdj:       180,
 				VolumeSpike:  180,
 				KeyBreakDown: 600,
+				NarrowRange:  900,
 			},
 		},
 		RiskAgent: RiskAgentConfig{
@@ -199,6 +447,12 @@ func Load(path string) (*Config, error) {
 			Model:     "gpt-4.1-mini",
 			TimeoutMs: 10000,
 		},
+		Notifications: NotificationsConfig{
+			Lark:           LarkConfig{TimeoutMs: 5000},
+			Webhook:        WebhookConfig{TimeoutMs: 5000},
+			DedupWindowSec: 60,
+			MinIntervalSec: 5,
+		},
 	}
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("parse config: %w", err)
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Manager owns the live Config loaded from a file on disk, reloading it
+// whenever the file changes (fsnotify) or the process receives SIGHUP, and
+// publishing each successfully-validated reload to subscribers. Current
+// always returns a fully-loaded, validated snapshot — a partial or invalid
+// reload is logged and rejected, leaving the previous snapshot in place.
+type Manager struct {
+	path string
+
+	mu      sync.RWMutex
+	current *Config
+
+	subMu sync.Mutex
+	subs  []chan *Config
+}
+
+// NewManager loads path once and returns a Manager wrapping the result.
+// Call Watch to start reacting to file changes and SIGHUP.
+func NewManager(path string) (*Manager, error) {
+	cfg, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return &Manager{path: path, current: cfg}, nil
+}
+
+// Current returns the most recently loaded and validated Config. Callers
+// must not mutate the returned value — it is shared with other readers and
+// replaced wholesale on reload, never edited in place.
+func (m *Manager) Current() *Config {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.current
+}
+
+// Subscribe returns a channel that receives every successful reload from
+// this point on. The channel is buffered by one and carries only the latest
+// config, so a subscriber that falls behind never processes a backlog of
+// stale reloads.
+func (m *Manager) Subscribe() <-chan *Config {
+	ch := make(chan *Config, 1)
+	m.subMu.Lock()
+	m.subs = append(m.subs, ch)
+	m.subMu.Unlock()
+	return ch
+}
+
+// Watch blocks, reloading on write/create/rename events for path (most
+// editors and config-management tools replace the file rather than
+// truncate-and-write it in place, which loses a watch on the original
+// inode, so the parent directory is watched instead and events are
+// filtered down to path) and on SIGHUP, until ctx is cancelled.
+func (m *Manager) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create fsnotify watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	dir := filepath.Dir(m.path)
+	if err := watcher.Add(dir); err != nil {
+		return fmt.Errorf("watch config dir %s: %w", dir, err)
+	}
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	target := filepath.Clean(m.path)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sighup:
+			log.Printf("config: SIGHUP received, reloading %s", m.path)
+			m.reload()
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(ev.Name) != target {
+				continue
+			}
+			if ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			m.reload()
+		case werr, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("config watcher error: %v", werr)
+		}
+	}
+}
+
+// reload loads and validates a fresh copy of the config file. A failure at
+// either step is logged and the previous snapshot is kept untouched — a
+// reload never leaves Current() returning a partially-applied config.
+func (m *Manager) reload() {
+	next, err := Load(m.path)
+	if err != nil {
+		log.Printf("config reload: read/parse error, keeping previous config: %v", err)
+		return
+	}
+	if err := validate(next); err != nil {
+		log.Printf("config reload rejected: %v", err)
+		return
+	}
+
+	m.mu.Lock()
+	prev := m.current
+	m.current = next
+	m.mu.Unlock()
+
+	logDiff(prev, next)
+	m.publish(next)
+}
+
+func (m *Manager) publish(next *Config) {
+	m.subMu.Lock()
+	subs := append([]chan *Config(nil), m.subs...)
+	m.subMu.Unlock()
+	for _, ch := range subs {
+		select {
+		case ch <- next:
+		default:
+			// A slow subscriber already has a pending reload queued; drop
+			// it in favor of this newer one instead of blocking.
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- next:
+			default:
+			}
+		}
+	}
+}
+
+// validate rejects a reload that is missing or has nonsensical values for
+// the settings every subsystem assumes are present, so a partial or
+// malformed edit to the config file never takes effect.
+func validate(cfg *Config) error {
+	if cfg == nil {
+		return fmt.Errorf("config is nil")
+	}
+	if cfg.Server.Port <= 0 {
+		return fmt.Errorf("server.port must be positive")
+	}
+	if len(cfg.Market.Symbols) == 0 {
+		return fmt.Errorf("market.symbols must not be empty")
+	}
+	if cfg.Market.PollIntervalSec <= 0 {
+		return fmt.Errorf("market.poll_interval_sec must be positive")
+	}
+	if cfg.Engine.WindowMaxKeep < 0 {
+		return fmt.Errorf("engine.window_max_keep must not be negative")
+	}
+	if cfg.Store.Sqlite.Path == "" && cfg.Store.DSN == "" {
+		return fmt.Errorf("store.sqlite.path or store.dsn must be set")
+	}
+	return nil
+}
+
+// logDiff reports which top-level Config sections changed between two
+// reloads, by name rather than by value — most sections hold secrets
+// (tokens, webhook URLs) that don't belong in a log line.
+func logDiff(prev, next *Config) {
+	if prev == nil || next == nil {
+		return
+	}
+	pv := reflect.ValueOf(*prev)
+	nv := reflect.ValueOf(*next)
+	t := pv.Type()
+
+	var changed []string
+	for i := 0; i < t.NumField(); i++ {
+		if !reflect.DeepEqual(pv.Field(i).Interface(), nv.Field(i).Interface()) {
+			changed = append(changed, t.Field(i).Name)
+		}
+	}
+	if len(changed) == 0 {
+		log.Printf("config reload: applied, no section changed")
+		return
+	}
+	log.Printf("config reload: applied, changed sections: %s", strings.Join(changed, ", "))
+}
@@ -11,6 +11,9 @@ import (
 	"time"
 
 	"ai-trading-assistant/internal/alert"
+	"ai-trading-assistant/internal/market/indicators"
+	"ai-trading-assistant/internal/metrics"
+	"ai-trading-assistant/internal/notifier"
 	"ai-trading-assistant/internal/riskagent"
 	"ai-trading-assistant/internal/store"
 )
@@ -18,8 +21,10 @@ import (
 type Config struct {
 	IndexRisk     IndexRiskConfig    `yaml:"index_risk"`
 	PanicDrop     PanicDropConfig    `yaml:"panic_drop"`
+	VolAdj        VolAdjConfig       `yaml:"vol_adj"`
 	VolumeSpike   VolumeSpikeConfig  `yaml:"volume_spike"`
 	KeyBreakDown  KeyBreakDownConfig `yaml:"key_break_down"`
+	NarrowRange   NarrowRangeConfig  `yaml:"narrow_range"`
 	WindowMaxKeep int                `yaml:"window_max_keep"`
 	CooldownSec   CooldownConfig     `yaml:"cooldown_sec"`
 }
@@ -36,6 +41,17 @@ type PanicDropConfig struct {
 	HighPct   float64 `yaml:"high_pct"`
 }
 
+// VolAdjConfig scores a symbol's drops against its own rolling
+// volatility instead of PanicDropConfig's fixed percentage: z = (r-μ)/σ
+// over the last ZWindowPoints log-returns. It coexists with PanicDrop
+// rather than replacing it, so a deployment can run both side by side
+// while tuning the new rule.
+type VolAdjConfig struct {
+	ZWindowPoints int     `yaml:"z_window_points"`
+	MedSigma      float64 `yaml:"med_sigma"`
+	HighSigma     float64 `yaml:"high_sigma"`
+}
+
 type VolumeSpikeConfig struct {
 	MaPoints int     `yaml:"ma_points"`
 	Ratio    float64 `yaml:"ratio"`
@@ -46,31 +62,82 @@ type KeyBreakDownConfig struct {
 	Priority string             `yaml:"priority"` // med/high
 }
 
+type NarrowRangeConfig struct {
+	N            int     `yaml:"n"`
+	BarPeriodSec int64   `yaml:"bar_period_sec"`
+	AtrPeriod    int     `yaml:"atr_period"`
+	AvgRangeN    int     `yaml:"avg_range_n"`
+	HighAtrPct   float64 `yaml:"high_atr_pct"` // ATR14 as % of price; at/above this, compression is "med", else "low"
+}
+
 type CooldownConfig struct {
 	IndexRisk    int `yaml:"index_risk"`
 	PanicDrop    int `yaml:"panic_drop"`
+	VolAdj       int `yaml:"vol_adj"`
 	VolumeSpike  int `yaml:"volume_spike"`
 	KeyBreakDown int `yaml:"key_break_down"`
+	NarrowRange  int `yaml:"narrow_range"`
+}
+
+// AlertSink is satisfied by anything that can take an outbound alert and
+// report back what happened to it. *alert.Service is the production
+// implementation; backtests supply an in-memory recorder instead so a
+// replay never sends a real notification.
+type AlertSink interface {
+	Handle(ctx context.Context, req alert.AlertRequest) alert.Result
 }
 
 type Engine struct {
-	cfg      Config
-	store    *store.Store
-	alertSvc *alert.Service
-	agent    *riskagent.Agent
+	cfg         Config
+	store       *store.Store
+	alertSvc    AlertSink
+	agent       *riskagent.Agent
+	notifierSvc *notifier.Service
+
+	mu          sync.Mutex
+	windows     map[string][]store.MarketSnapshot
+	cooldown    map[string]int64
+	narrowRange map[string]*indicators.NarrowRangeDetector
+	volAdj      map[string]*indicators.VolAdjDetector
+	clock       func() time.Time
+}
+
+func New(cfg Config, st *store.Store, alertSvc AlertSink, agent *riskagent.Agent, notifierSvc *notifier.Service) *Engine {
+	cfg = applyDefaults(cfg)
 
-	mu       sync.Mutex
-	windows  map[string][]store.MarketSnapshot
-	cooldown map[string]int64
+	return &Engine{
+		cfg:         cfg,
+		store:       st,
+		alertSvc:    alertSvc,
+		agent:       agent,
+		notifierSvc: notifierSvc,
+		windows:     make(map[string][]store.MarketSnapshot),
+		cooldown:    make(map[string]int64),
+		narrowRange: make(map[string]*indicators.NarrowRangeDetector),
+		volAdj:      make(map[string]*indicators.VolAdjDetector),
+		clock:       time.Now,
+	}
 }
 
-func New(cfg Config, st *store.Store, alertSvc *alert.Service, agent *riskagent.Agent) *Engine {
+// applyDefaults fills in the zero-value fallbacks New and SetConfig both
+// need, so a reload that only sets a few fields (or a caller that never
+// touches most of Config) still gets sane thresholds everywhere else.
+func applyDefaults(cfg Config) Config {
 	if cfg.IndexRisk.Symbol == "" {
 		cfg.IndexRisk.Symbol = "sh000001"
 	}
 	if cfg.PanicDrop.WindowSec <= 0 {
 		cfg.PanicDrop.WindowSec = 300
 	}
+	if cfg.VolAdj.ZWindowPoints <= 1 {
+		cfg.VolAdj.ZWindowPoints = 60
+	}
+	if cfg.VolAdj.MedSigma <= 0 {
+		cfg.VolAdj.MedSigma = 2.0
+	}
+	if cfg.VolAdj.HighSigma <= 0 {
+		cfg.VolAdj.HighSigma = 3.0
+	}
 	if cfg.VolumeSpike.MaPoints <= 1 {
 		cfg.VolumeSpike.MaPoints = 5
 	}
@@ -80,6 +147,21 @@ func New(cfg Config, st *store.Store, alertSvc *alert.Service, agent *riskagent.
 	if cfg.KeyBreakDown.Priority == "" {
 		cfg.KeyBreakDown.Priority = "med"
 	}
+	if cfg.NarrowRange.N <= 0 {
+		cfg.NarrowRange.N = 4
+	}
+	if cfg.NarrowRange.BarPeriodSec <= 0 {
+		cfg.NarrowRange.BarPeriodSec = 300
+	}
+	if cfg.NarrowRange.AtrPeriod <= 0 {
+		cfg.NarrowRange.AtrPeriod = 14
+	}
+	if cfg.NarrowRange.AvgRangeN <= 0 {
+		cfg.NarrowRange.AvgRangeN = 20
+	}
+	if cfg.NarrowRange.HighAtrPct <= 0 {
+		cfg.NarrowRange.HighAtrPct = 1.0
+	}
 	if cfg.WindowMaxKeep <= 0 {
 		cfg.WindowMaxKeep = 200
 	}
@@ -89,21 +171,71 @@ func New(cfg Config, st *store.Store, alertSvc *alert.Service, agent *riskagent.
 	if cfg.CooldownSec.PanicDrop <= 0 {
 		cfg.CooldownSec.PanicDrop = 180
 	}
+	if cfg.CooldownSec.VolAdj <= 0 {
+		cfg.CooldownSec.VolAdj = 180
+	}
 	if cfg.CooldownSec.VolumeSpike <= 0 {
 		cfg.CooldownSec.VolumeSpike = 180
 	}
 	if cfg.CooldownSec.KeyBreakDown <= 0 {
 		cfg.CooldownSec.KeyBreakDown = 600
 	}
+	if cfg.CooldownSec.NarrowRange <= 0 {
+		cfg.CooldownSec.NarrowRange = 900
+	}
+	return cfg
+}
 
-	return &Engine{
-		cfg:      cfg,
-		store:    st,
-		alertSvc: alertSvc,
-		agent:    agent,
-		windows:  make(map[string][]store.MarketSnapshot),
-		cooldown: make(map[string]int64),
+// SetConfig swaps the engine's tunables at runtime — thresholds, cooldowns,
+// window sizes — so a config.Manager reload takes effect without
+// restarting the process. Per-symbol rolling state (windows, cooldown
+// timestamps, the narrow-range/vol-adj detectors) is left untouched; only
+// the rules' configuration changes.
+func (e *Engine) SetConfig(cfg Config) {
+	cfg = applyDefaults(cfg)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cfg = cfg
+}
+
+// SetClock overrides the engine's source of "now", used by OnSnapshot to
+// default a zero snapshot TS and by checkCooldown to gate repeat alerts.
+// Tests (enginetest) inject a fake clock so a replay's cooldown windows
+// follow the vector's own snapshot timestamps instead of wall-clock time.
+// A nil clock resets to time.Now.
+func (e *Engine) SetClock(clock func() time.Time) {
+	if clock == nil {
+		clock = time.Now
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.clock = clock
+}
+
+func (e *Engine) now() time.Time {
+	e.mu.Lock()
+	clock := e.clock
+	e.mu.Unlock()
+	if clock == nil {
+		return time.Now()
 	}
+	return clock()
+}
+
+// Config returns the engine's current (defaulted) configuration, the one
+// New built it with or the latest SetConfig, so callers that need to construct an equivalent fresh engine elsewhere
+// (the backtest subsystem) don't have to re-derive the defaulting logic in
+// New.
+func (e *Engine) Config() Config {
+	return e.getConfig()
+}
+
+// getConfig returns the engine's current Config under e.mu, so a concurrent
+// SetConfig reload never races with a rule reading thresholds mid-decision.
+func (e *Engine) getConfig() Config {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.cfg
 }
 
 func (e *Engine) OnSnapshot(s store.MarketSnapshot) {
@@ -112,21 +244,25 @@ func (e *Engine) OnSnapshot(s store.MarketSnapshot) {
 		return
 	}
 	if s.TS == 0 {
-		s.TS = time.Now().Unix()
+		s.TS = e.now().Unix()
 	}
 
+	metrics.EngineSnapshotLagSeconds.Observe(e.now().Sub(time.Unix(s.TS, 0)).Seconds())
+
+	maxKeep := e.getConfig().WindowMaxKeep
+
 	e.mu.Lock()
 	window := e.windows[s.Symbol]
 	window = append(window, s)
-	window = e.trimWindow(window, s.TS)
+	window = trimWindow(window, maxKeep)
 	e.windows[s.Symbol] = window
+	metrics.EngineWindowsGauge.Set(float64(len(e.windows)))
 	e.mu.Unlock()
 
 	e.runRules(s, window)
 }
 
-func (e *Engine) trimWindow(window []store.MarketSnapshot, now int64) []store.MarketSnapshot {
-	maxKeep := e.cfg.WindowMaxKeep
+func trimWindow(window []store.MarketSnapshot, maxKeep int) []store.MarketSnapshot {
 	if maxKeep > 0 && len(window) > maxKeep {
 		window = window[len(window)-maxKeep:]
 	}
@@ -136,40 +272,44 @@ func (e *Engine) trimWindow(window []store.MarketSnapshot, now int64) []store.Ma
 func (e *Engine) runRules(s store.MarketSnapshot, window []store.MarketSnapshot) {
 	e.ruleIndexRisk(s)
 	e.rulePanicDrop(s, window)
+	e.ruleVolAdjDrop(s)
 	e.ruleVolumeSpike(s, window)
 	e.ruleKeyBreakDown(s)
+	e.ruleNarrowRange(s)
 }
 
 func (e *Engine) ruleIndexRisk(s store.MarketSnapshot) {
-	if s.Symbol != strings.ToLower(e.cfg.IndexRisk.Symbol) {
+	cfg := e.getConfig()
+	if s.Symbol != strings.ToLower(cfg.IndexRisk.Symbol) {
 		return
 	}
 	if s.ChangePct == 0 {
 		return
 	}
-	if s.ChangePct <= -e.cfg.IndexRisk.HighPct {
-		if !e.checkCooldown("INDEX_RISK", s.Symbol, "high", e.cfg.CooldownSec.IndexRisk) {
+	if s.ChangePct <= -cfg.IndexRisk.HighPct {
+		if !e.checkCooldown("INDEX_RISK", s.Symbol, "high", cfg.CooldownSec.IndexRisk) {
 			return
 		}
-		e.emit("INDEX_RISK", "high", s, map[string]any{"change_pct": s.ChangePct, "threshold": e.cfg.IndexRisk.HighPct})
+		e.emit("INDEX_RISK", "high", s, map[string]any{"change_pct": s.ChangePct, "threshold": cfg.IndexRisk.HighPct})
 		return
 	}
-	if s.ChangePct <= -e.cfg.IndexRisk.MedPct {
-		if !e.checkCooldown("INDEX_RISK", s.Symbol, "med", e.cfg.CooldownSec.IndexRisk) {
+	if s.ChangePct <= -cfg.IndexRisk.MedPct {
+		if !e.checkCooldown("INDEX_RISK", s.Symbol, "med", cfg.CooldownSec.IndexRisk) {
 			return
 		}
-		e.emit("INDEX_RISK", "med", s, map[string]any{"change_pct": s.ChangePct, "threshold": e.cfg.IndexRisk.MedPct})
+		e.emit("INDEX_RISK", "med", s, map[string]any{"change_pct": s.ChangePct, "threshold": cfg.IndexRisk.MedPct})
 	}
 }
 
 func (e *Engine) rulePanicDrop(s store.MarketSnapshot, window []store.MarketSnapshot) {
+	cfg := e.getConfig()
 	if !isStockSymbol(s.Symbol) {
 		return
 	}
-	if e.cfg.PanicDrop.WindowSec <= 0 || len(window) < 2 {
+	if cfg.PanicDrop.WindowSec <= 0 || len(window) < 2 {
 		return
 	}
-	cutoff := s.TS - int64(e.cfg.PanicDrop.WindowSec)
+	cutoff := s.TS - int64(cfg.PanicDrop.WindowSec)
 	maxPrice := 0.0
 	for i := len(window) - 1; i >= 0; i-- {
 		if window[i].TS < cutoff {
@@ -183,29 +323,69 @@ func (e *Engine) rulePanicDrop(s store.MarketSnapshot, window []store.MarketSnap
 		return
 	}
 	drawdownPct := (s.Price - maxPrice) / maxPrice * 100
-	if drawdownPct <= -e.cfg.PanicDrop.HighPct {
-		if !e.checkCooldown("PANIC_DROP", s.Symbol, "high", e.cfg.CooldownSec.PanicDrop) {
+	if drawdownPct <= -cfg.PanicDrop.HighPct {
+		if !e.checkCooldown("PANIC_DROP", s.Symbol, "high", cfg.CooldownSec.PanicDrop) {
 			return
 		}
-		e.emit("PANIC_DROP", "high", s, map[string]any{"drawdown_pct": drawdownPct, "window_sec": e.cfg.PanicDrop.WindowSec, "threshold": e.cfg.PanicDrop.HighPct})
+		e.emit("PANIC_DROP", "high", s, map[string]any{"drawdown_pct": drawdownPct, "window_sec": cfg.PanicDrop.WindowSec, "threshold": cfg.PanicDrop.HighPct})
 		return
 	}
-	if drawdownPct <= -e.cfg.PanicDrop.MedPct {
-		if !e.checkCooldown("PANIC_DROP", s.Symbol, "med", e.cfg.CooldownSec.PanicDrop) {
+	if drawdownPct <= -cfg.PanicDrop.MedPct {
+		if !e.checkCooldown("PANIC_DROP", s.Symbol, "med", cfg.CooldownSec.PanicDrop) {
 			return
 		}
-		e.emit("PANIC_DROP", "med", s, map[string]any{"drawdown_pct": drawdownPct, "window_sec": e.cfg.PanicDrop.WindowSec, "threshold": e.cfg.PanicDrop.MedPct})
+		e.emit("PANIC_DROP", "med", s, map[string]any{"drawdown_pct": drawdownPct, "window_sec": cfg.PanicDrop.WindowSec, "threshold": cfg.PanicDrop.MedPct})
 	}
 }
 
+// ruleVolAdjDrop scores each stock's latest return against its own rolling
+// volatility regime instead of a fixed percentage, so a 2% drop on a
+// normally-placid symbol can flag while the same move on an already-volatile
+// one does not. It coexists with rulePanicDrop rather than replacing it.
+func (e *Engine) ruleVolAdjDrop(s store.MarketSnapshot) {
+	cfg := e.getConfig()
+	if !isStockSymbol(s.Symbol) {
+		return
+	}
+	if s.Price <= 0 {
+		return
+	}
+
+	e.mu.Lock()
+	det, ok := e.volAdj[s.Symbol]
+	if !ok {
+		det = indicators.NewVolAdjDetector(cfg.VolAdj.ZWindowPoints)
+		e.volAdj[s.Symbol] = det
+	}
+	z, ready := det.Add(s.Price)
+	e.mu.Unlock()
+
+	if !ready || z > -cfg.VolAdj.MedSigma {
+		return
+	}
+
+	severity := "med"
+	threshold := cfg.VolAdj.MedSigma
+	if z <= -cfg.VolAdj.HighSigma {
+		severity = "high"
+		threshold = cfg.VolAdj.HighSigma
+	}
+
+	if !e.checkCooldown("VOL_ADJ_DROP", s.Symbol, severity, cfg.CooldownSec.VolAdj) {
+		return
+	}
+	e.emit("VOL_ADJ_DROP", severity, s, map[string]any{"z": z, "threshold": threshold, "z_window_points": cfg.VolAdj.ZWindowPoints})
+}
+
 func (e *Engine) ruleVolumeSpike(s store.MarketSnapshot, window []store.MarketSnapshot) {
+	cfg := e.getConfig()
 	if !isStockSymbol(s.Symbol) {
 		return
 	}
-	if e.cfg.VolumeSpike.MaPoints <= 1 || len(window) < e.cfg.VolumeSpike.MaPoints {
+	if cfg.VolumeSpike.MaPoints <= 1 || len(window) < cfg.VolumeSpike.MaPoints {
 		return
 	}
-	start := len(window) - e.cfg.VolumeSpike.MaPoints
+	start := len(window) - cfg.VolumeSpike.MaPoints
 	if start < 0 {
 		start = 0
 	}
@@ -225,8 +405,8 @@ func (e *Engine) ruleVolumeSpike(s store.MarketSnapshot, window []store.MarketSn
 		return
 	}
 	ratio := s.Volume / avg
-	if ratio >= e.cfg.VolumeSpike.Ratio {
-		if !e.checkCooldown("VOLUME_SPIKE", s.Symbol, "med", e.cfg.CooldownSec.VolumeSpike) {
+	if ratio >= cfg.VolumeSpike.Ratio {
+		if !e.checkCooldown("VOLUME_SPIKE", s.Symbol, "med", cfg.CooldownSec.VolumeSpike) {
 			return
 		}
 		e.emit("VOLUME_SPIKE", "med", s, map[string]any{"ratio": ratio, "avg": avg})
@@ -234,13 +414,14 @@ func (e *Engine) ruleVolumeSpike(s store.MarketSnapshot, window []store.MarketSn
 }
 
 func (e *Engine) ruleKeyBreakDown(s store.MarketSnapshot) {
+	cfg := e.getConfig()
 	if !isStockSymbol(s.Symbol) {
 		return
 	}
-	if len(e.cfg.KeyBreakDown.Levels) == 0 {
+	if len(cfg.KeyBreakDown.Levels) == 0 {
 		return
 	}
-	level, ok := e.cfg.KeyBreakDown.Levels[s.Symbol]
+	level, ok := cfg.KeyBreakDown.Levels[s.Symbol]
 	if !ok {
 		return
 	}
@@ -248,17 +429,61 @@ func (e *Engine) ruleKeyBreakDown(s store.MarketSnapshot) {
 		return
 	}
 	if s.Price < level {
-		severity := strings.ToLower(e.cfg.KeyBreakDown.Priority)
+		severity := strings.ToLower(cfg.KeyBreakDown.Priority)
 		if severity != "high" {
 			severity = "med"
 		}
-		if !e.checkCooldown("KEY_BREAK_DOWN", s.Symbol, severity, e.cfg.CooldownSec.KeyBreakDown) {
+		if !e.checkCooldown("KEY_BREAK_DOWN", s.Symbol, severity, cfg.CooldownSec.KeyBreakDown) {
 			return
 		}
 		e.emit("KEY_BREAK_DOWN", severity, s, map[string]any{"level": level})
 	}
 }
 
+func (e *Engine) ruleNarrowRange(s store.MarketSnapshot) {
+	cfg := e.getConfig()
+	if !isStockSymbol(s.Symbol) {
+		return
+	}
+	if s.Price <= 0 {
+		return
+	}
+
+	e.mu.Lock()
+	det, ok := e.narrowRange[s.Symbol]
+	if !ok {
+		det = indicators.NewNarrowRangeDetector(cfg.NarrowRange.N, cfg.NarrowRange.BarPeriodSec, cfg.NarrowRange.AtrPeriod, cfg.NarrowRange.AvgRangeN)
+		e.narrowRange[s.Symbol] = det
+	}
+	_, ev, closed, isNRn := det.Add(s.TS, s.Price, s.Volume)
+	e.mu.Unlock()
+
+	if !closed || !isNRn {
+		return
+	}
+
+	severity := "low"
+	atrPct := 0.0
+	if s.Price > 0 {
+		atrPct = ev.ATR14 / s.Price * 100
+	}
+	if atrPct >= cfg.NarrowRange.HighAtrPct {
+		severity = "med"
+	}
+
+	if !e.checkCooldown("NARROW_RANGE", s.Symbol, severity, cfg.CooldownSec.NarrowRange) {
+		return
+	}
+	e.emit("NARROW_RANGE", severity, s, map[string]any{
+		"n":                   ev.N,
+		"range":               ev.Range,
+		"avg_range_20":        ev.AvgRange20,
+		"atr14":               ev.ATR14,
+		"breakout_level_up":   ev.BreakoutLevelUp,
+		"breakout_level_down": ev.BreakoutLevelDown,
+	})
+}
+
 func (e *Engine) emit(eventType string, severity string, s store.MarketSnapshot, evidence map[string]any) {
 	if e.store == nil {
 		log.Printf("event store not configured, drop event=%s", eventType)
@@ -267,6 +492,7 @@ func (e *Engine) emit(eventType string, severity string, s store.MarketSnapshot,
 	if severity == "" {
 		severity = "med"
 	}
+	metrics.EngineRuleFiresTotal.WithLabelValues(eventType, severity, s.Symbol).Inc()
 
 	windowTag := ""
 	if v, ok := evidence["window_sec"]; ok {
@@ -313,6 +539,9 @@ func (e *Engine) emit(eventType string, severity string, s store.MarketSnapshot,
 		priority = alert.PriorityLow
 	}
 	markdown := riskagent.FormatMarkdown(evt.Title, decision)
+	if e.notifierSvc != nil {
+		e.notifierSvc.Notify(context.Background(), s.Symbol, eventType, evt.Title, markdown, decision.Severity)
+	}
 	alertReq := alert.AlertRequest{
 		Priority: priority,
 		Group:    "risk",
@@ -406,6 +635,10 @@ func buildEventTitle(eventType string, s store.MarketSnapshot, evidence map[stri
 			}
 			return fmt.Sprintf("%s PANIC_DROP drawdown=%v", s.Symbol, v)
 		}
+	case "NARROW_RANGE":
+		if n, ok := evidence["n"]; ok {
+			return fmt.Sprintf("%s NR%v range=%v", s.Symbol, n, evidence["range"])
+		}
 	}
 	return fmt.Sprintf("%s %s", s.Symbol, eventType)
 }
@@ -432,14 +665,16 @@ func (e *Engine) checkCooldown(ruleType, symbol, severity string, cooldownSec in
 		return true
 	}
 	key := fmt.Sprintf("%s:%s:%s", ruleType, symbol, severity)
-	now := time.Now().Unix()
+	now := e.now().Unix()
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	if last, ok := e.cooldown[key]; ok {
 		if now-last < int64(cooldownSec) {
+			metrics.EngineCooldownSuppressedTotal.WithLabelValues(ruleType, symbol).Inc()
 			return false
 		}
 	}
 	e.cooldown[key] = now
+	metrics.EngineCooldownGauge.Set(float64(len(e.cooldown)))
 	return true
 }
@@ -0,0 +1,78 @@
+package enginetest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"ai-trading-assistant/internal/alert"
+	"ai-trading-assistant/internal/engine"
+	"ai-trading-assistant/internal/store"
+)
+
+// Replay drives every snapshot in v through a freshly constructed engine
+// (scratch sqlite store, no alert/notifier side effects) and returns every
+// event it produced, in the order the engine emitted them.
+func Replay(cfg engine.Config, v Vector) ([]store.EventRecord, error) {
+	st, cleanup, err := openScratchStore()
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	eng := engine.New(cfg, st, recordingSink{}, nil, nil)
+
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		return nil, fmt.Errorf("load tz: %w", err)
+	}
+	dates := make(map[string]struct{})
+	for _, s := range v.Snapshots {
+		eng.SetClock(func() time.Time { return time.Unix(s.TS, 0) })
+		eng.OnSnapshot(s)
+		dates[time.Unix(s.TS, 0).In(loc).Format("2006-01-02")] = struct{}{}
+	}
+
+	var events []store.EventRecord
+	for date := range dates {
+		recs, err := st.QueryEventsByDate(date, "", 1000, 0)
+		if err != nil {
+			return nil, fmt.Errorf("query events for %s: %w", date, err)
+		}
+		events = append(events, recs...)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].TS != events[j].TS {
+			return events[i].TS < events[j].TS
+		}
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
+
+// recordingSink is a no-op engine.AlertSink: a replay must never send a real
+// alert, mirroring the in-memory recorder internal/backtest uses for the
+// same reason.
+type recordingSink struct{}
+
+func (recordingSink) Handle(_ context.Context, req alert.AlertRequest) alert.Result {
+	return alert.Result{Status: alert.StatusSent}
+}
+
+func openScratchStore() (*store.Store, func(), error) {
+	path := filepath.Join(os.TempDir(), fmt.Sprintf("enginetest-%d-%d.db", os.Getpid(), time.Now().UnixNano()))
+	st, err := store.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open scratch store: %w", err)
+	}
+	cleanup := func() {
+		_ = st.Close()
+		_ = os.Remove(path)
+		_ = os.Remove(path + "-wal")
+		_ = os.Remove(path + "-shm")
+	}
+	return st, cleanup, nil
+}
@@ -0,0 +1,53 @@
+package engine_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"ai-trading-assistant/internal/engine"
+	"ai-trading-assistant/internal/engine/enginetest"
+)
+
+// TestVectors replays every testdata/vectors/*.json conformance vector and
+// asserts the produced events match the vector's expected_events, ignoring
+// TS/evidence jitter. Vectors are the regression guard for rule math
+// (IndexRisk thresholds, PanicDrop window math, VolumeSpike MA window): a
+// change that shifts a threshold or a window boundary should show up here
+// before it reaches production.
+func TestVectors(t *testing.T) {
+	files, err := filepath.Glob("testdata/vectors/*.json")
+	if err != nil {
+		t.Fatalf("glob vectors: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no vectors found under testdata/vectors")
+	}
+
+	cfg := engine.Config{
+		IndexRisk: engine.IndexRiskConfig{Symbol: "sh000001", MedPct: 3, HighPct: 5},
+	}
+
+	for _, f := range files {
+		f := f
+		t.Run(filepath.Base(f), func(t *testing.T) {
+			v, err := enginetest.LoadVector(f)
+			if err != nil {
+				t.Fatalf("load vector: %v", err)
+			}
+			events, err := enginetest.Replay(cfg, v)
+			if err != nil {
+				t.Fatalf("replay: %v", err)
+			}
+			if len(events) != len(v.ExpectedEvents) {
+				t.Fatalf("got %d events, want %d: %+v", len(events), len(v.ExpectedEvents), events)
+			}
+			for i, evt := range events {
+				got := enginetest.ToExpected(evt)
+				want := v.ExpectedEvents[i]
+				if got != want {
+					t.Errorf("event %d: got %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
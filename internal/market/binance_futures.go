@@ -0,0 +1,133 @@
+package market
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// BinanceFuturesProvider implements FuturesProvider against Binance's
+// USDT-M futures REST API (fapi), combining the premium index (mark/index
+// price, funding rate) with the last trade price per symbol.
+type BinanceFuturesProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewBinanceFuturesProvider(timeout time.Duration) *BinanceFuturesProvider {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &BinanceFuturesProvider{
+		baseURL: "https://fapi.binance.com",
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+type binancePremiumIndex struct {
+	Symbol          string `json:"symbol"`
+	MarkPrice       string `json:"markPrice"`
+	IndexPrice      string `json:"indexPrice"`
+	LastFundingRate string `json:"lastFundingRate"`
+	NextFundingTime int64  `json:"nextFundingTime"`
+}
+
+type binanceTickerPrice struct {
+	Symbol string `json:"symbol"`
+	Price  string `json:"price"`
+}
+
+func (p *BinanceFuturesProvider) GetContractQuotes(ctx context.Context, symbols []string) ([]Quote, string, error) {
+	if len(symbols) == 0 {
+		return nil, "", fmt.Errorf("symbols is empty")
+	}
+
+	premiums, err := p.fetchPremiumIndex(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	prices, err := p.fetchTickerPrices(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	out := make([]Quote, 0, len(symbols))
+	for _, sym := range symbols {
+		key := strings.ToUpper(strings.TrimSpace(sym))
+		prem, ok := premiums[key]
+		if !ok {
+			return nil, "", fmt.Errorf("no premium index for %s", sym)
+		}
+		price, ok := prices[key]
+		if !ok || price <= 0 {
+			return nil, "", fmt.Errorf("no ticker price for %s", sym)
+		}
+
+		rawBytes, _ := json.Marshal(prem)
+		out = append(out, Quote{
+			Symbol: strings.ToLower(sym),
+			Price:  price,
+			TS:     time.Now().Unix(),
+			Raw:    string(rawBytes),
+			Contract: &ContractInfo{
+				MarkPrice:     parseFloat(prem.MarkPrice),
+				IndexPrice:    parseFloat(prem.IndexPrice),
+				FundingRate:   parseFloat(prem.LastFundingRate),
+				NextFundingTS: prem.NextFundingTime / 1000,
+				PositionSide:  "both",
+			},
+		})
+	}
+	return out, "binance-futures", nil
+}
+
+func (p *BinanceFuturesProvider) fetchPremiumIndex(ctx context.Context) (map[string]binancePremiumIndex, error) {
+	var list []binancePremiumIndex
+	if err := p.getJSON(ctx, "/fapi/v1/premiumIndex", nil, &list); err != nil {
+		return nil, fmt.Errorf("request binance premium index: %w", err)
+	}
+	out := make(map[string]binancePremiumIndex, len(list))
+	for _, item := range list {
+		out[strings.ToUpper(item.Symbol)] = item
+	}
+	return out, nil
+}
+
+func (p *BinanceFuturesProvider) fetchTickerPrices(ctx context.Context) (map[string]float64, error) {
+	var list []binanceTickerPrice
+	if err := p.getJSON(ctx, "/fapi/v1/ticker/price", nil, &list); err != nil {
+		return nil, fmt.Errorf("request binance ticker price: %w", err)
+	}
+	out := make(map[string]float64, len(list))
+	for _, item := range list {
+		out[strings.ToUpper(item.Symbol)] = parseFloat(item.Price)
+	}
+	return out, nil
+}
+
+func (p *BinanceFuturesProvider) getJSON(ctx context.Context, path string, query url.Values, dst any) error {
+	u, err := url.Parse(p.baseURL + path)
+	if err != nil {
+		return fmt.Errorf("invalid base url: %w", err)
+	}
+	if query != nil {
+		u.RawQuery = query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
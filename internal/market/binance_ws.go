@@ -0,0 +1,116 @@
+package market
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// BinanceWSProvider streams trade ticks from Binance's combined-stream
+// WebSocket gateway. Set Futures to subscribe against the USDT-M futures
+// endpoint instead of spot.
+type BinanceWSProvider struct {
+	Futures bool
+}
+
+func NewBinanceWSProvider(futures bool) *BinanceWSProvider {
+	return &BinanceWSProvider{Futures: futures}
+}
+
+func (p *BinanceWSProvider) Name() string {
+	if p.Futures {
+		return "binance-futures-ws"
+	}
+	return "binance-spot-ws"
+}
+
+func (p *BinanceWSProvider) Subscribe(ctx context.Context, symbols []string) (<-chan Quote, error) {
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("symbols is empty")
+	}
+	streams := make([]string, 0, len(symbols))
+	for _, s := range symbols {
+		streams = append(streams, strings.ToLower(s)+"@trade")
+	}
+	host := "stream.binance.com:9443"
+	if p.Futures {
+		host = "fstream.binance.com"
+	}
+	url := fmt.Sprintf("wss://%s/stream?streams=%s", host, strings.Join(streams, "/"))
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("dial binance ws: %w", err)
+	}
+
+	out := make(chan Quote, 256)
+	startWSHeartbeat(ctx, conn)
+	go func() {
+		defer close(out)
+		defer conn.Close()
+		go closeOnDone(ctx, conn)
+		for {
+			_, raw, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if q, ok := parseBinanceTrade(raw); ok {
+				select {
+				case out <- q:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+type binanceEnvelope struct {
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+}
+
+type binanceTrade struct {
+	Symbol      string `json:"s"`
+	Price       string `json:"p"`
+	Qty         string `json:"q"`
+	TradeTimeMs int64  `json:"T"`
+}
+
+func parseBinanceTrade(raw []byte) (Quote, bool) {
+	var env binanceEnvelope
+	if err := json.Unmarshal(raw, &env); err != nil || len(env.Data) == 0 {
+		return Quote{}, false
+	}
+	var t binanceTrade
+	if err := json.Unmarshal(env.Data, &t); err != nil {
+		return Quote{}, false
+	}
+	price, err := strconv.ParseFloat(t.Price, 64)
+	if err != nil || price <= 0 {
+		return Quote{}, false
+	}
+	qty, _ := strconv.ParseFloat(t.Qty, 64)
+	ts := t.TradeTimeMs / 1000
+	if ts == 0 {
+		ts = time.Now().Unix()
+	}
+	return Quote{
+		Symbol: strings.ToLower(t.Symbol),
+		Price:  price,
+		Volume: qty,
+		TS:     ts,
+		Raw:    string(raw),
+	}, true
+}
+
+func closeOnDone(ctx context.Context, conn *websocket.Conn) {
+	<-ctx.Done()
+	_ = conn.Close()
+}
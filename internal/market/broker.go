@@ -0,0 +1,49 @@
+package market
+
+import "sync"
+
+// Broker fans out every ingested Quote to subscribers (WebSocket streaming
+// handlers). Publish never blocks on a slow subscriber: each subscriber
+// channel is buffered, and a full channel simply drops the update rather
+// than stalling the ingest path.
+type Broker struct {
+	mu     sync.Mutex
+	nextID int
+	subs   map[int]chan Quote
+}
+
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[int]chan Quote)}
+}
+
+// Subscribe registers a new subscriber and returns its channel plus an
+// unsubscribe func the caller must invoke when done (typically via defer).
+func (b *Broker) Subscribe() (<-chan Quote, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	ch := make(chan Quote, 32)
+	b.subs[id] = ch
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if ch, ok := b.subs[id]; ok {
+			delete(b.subs, id)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
+
+func (b *Broker) Publish(q Quote) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.subs {
+		select {
+		case ch <- q:
+		default:
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package market
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitBreaker opens after `threshold` consecutive failures and stays
+// open for `openFor` before allowing a single trial call through again.
+type circuitBreaker struct {
+	mu          sync.Mutex
+	threshold   int
+	openFor     time.Duration
+	failures    int
+	openUntil   time.Time
+	lastErr     error
+	lastSuccess time.Time
+	lastFailure time.Time
+}
+
+func newCircuitBreaker(threshold int, openFor time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if openFor <= 0 {
+		openFor = 30 * time.Second
+	}
+	return &circuitBreaker{threshold: threshold, openFor: openFor}
+}
+
+// allow reports whether a call should be attempted right now.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openUntil.IsZero() {
+		return true
+	}
+	return !time.Now().Before(b.openUntil)
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.openUntil = time.Time{}
+	b.lastSuccess = time.Now()
+}
+
+func (b *circuitBreaker) recordFailure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.lastErr = err
+	b.lastFailure = time.Now()
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.openFor)
+	}
+}
+
+// snapshot returns the breaker's current status for health reporting.
+func (b *circuitBreaker) snapshot() (open bool, lastErr error, lastSuccess, lastFailure time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	open = !b.openUntil.IsZero() && time.Now().Before(b.openUntil)
+	return open, b.lastErr, b.lastSuccess, b.lastFailure
+}
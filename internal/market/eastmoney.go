@@ -57,7 +57,7 @@ func (p *EastmoneyProvider) GetQuotes(ctx context.Context, symbols []string) ([]
 }
 
 func (p *EastmoneyProvider) getOne(ctx context.Context, symbol string) (Quote, error) {
-	secid, err := toSecID(symbol)
+	secid, err := ToSecID(symbol)
 	if err != nil {
 		return Quote{}, err
 	}
@@ -126,17 +126,6 @@ func (p *EastmoneyProvider) getOne(ctx context.Context, symbol string) (Quote, e
 	}, nil
 }
 
-func toSecID(symbol string) (string, error) {
-	s := strings.ToLower(strings.TrimSpace(symbol))
-	if strings.HasPrefix(s, "sh") {
-		return "1." + strings.TrimPrefix(s, "sh"), nil
-	}
-	if strings.HasPrefix(s, "sz") {
-		return "0." + strings.TrimPrefix(s, "sz"), nil
-	}
-	return "", fmt.Errorf("invalid symbol: %s", symbol)
-}
-
 func shouldRetry(err error) bool {
 	if err == nil {
 		return false
@@ -0,0 +1,140 @@
+package market
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// GenericRESTConfig describes a REST quote source configured entirely from
+// YAML, for feeds that don't warrant their own Go provider file. URLTemplate
+// must contain a "{symbol}" placeholder; the *Path fields are dot-separated
+// paths into the decoded JSON response (e.g. "data.price").
+type GenericRESTConfig struct {
+	Name          string `yaml:"name"`
+	URLTemplate   string `yaml:"url_template"`
+	PricePath     string `yaml:"price_path"`
+	ChangePctPath string `yaml:"change_pct_path"`
+	VolumePath    string `yaml:"volume_path"`
+	NamePath      string `yaml:"name_path"`
+	TimeoutMs     int    `yaml:"timeout_ms"`
+}
+
+// GenericRESTProvider fetches one symbol at a time from a YAML-configured
+// REST endpoint and extracts fields by JSON path.
+type GenericRESTProvider struct {
+	cfg    GenericRESTConfig
+	client *http.Client
+}
+
+func NewGenericRESTProvider(cfg GenericRESTConfig) *GenericRESTProvider {
+	timeout := time.Duration(cfg.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	if cfg.Name == "" {
+		cfg.Name = "generic_rest"
+	}
+	return &GenericRESTProvider{cfg: cfg, client: &http.Client{Timeout: timeout}}
+}
+
+func (p *GenericRESTProvider) GetQuotes(ctx context.Context, symbols []string) ([]Quote, string, error) {
+	if len(symbols) == 0 {
+		return nil, "", fmt.Errorf("symbols is empty")
+	}
+	if p.cfg.URLTemplate == "" || p.cfg.PricePath == "" {
+		return nil, "", fmt.Errorf("%s: url_template/price_path not configured", p.cfg.Name)
+	}
+
+	out := make([]Quote, 0, len(symbols))
+	for _, sym := range symbols {
+		q, err := p.getOne(ctx, sym)
+		if err != nil {
+			return nil, "", err
+		}
+		out = append(out, q)
+	}
+	return out, p.cfg.Name, nil
+}
+
+func (p *GenericRESTProvider) getOne(ctx context.Context, symbol string) (Quote, error) {
+	url := strings.ReplaceAll(p.cfg.URLTemplate, "{symbol}", symbol)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Quote{}, fmt.Errorf("build request: %w", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Quote{}, fmt.Errorf("request %s: %w", p.cfg.Name, err)
+	}
+	defer resp.Body.Close()
+
+	var doc any
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return Quote{}, fmt.Errorf("decode %s: %w", p.cfg.Name, err)
+	}
+
+	price, ok := jsonPathFloat(doc, p.cfg.PricePath)
+	if !ok || price <= 0 {
+		return Quote{}, fmt.Errorf("%s: missing/invalid price for %s", p.cfg.Name, symbol)
+	}
+	changePct, _ := jsonPathFloat(doc, p.cfg.ChangePctPath)
+	volume, _ := jsonPathFloat(doc, p.cfg.VolumePath)
+	name, _ := jsonPathString(doc, p.cfg.NamePath)
+
+	return Quote{
+		Symbol:    strings.ToLower(symbol),
+		Name:      name,
+		Price:     price,
+		ChangePct: changePct,
+		Volume:    volume,
+		TS:        time.Now().Unix(),
+	}, nil
+}
+
+// jsonPathFloat/jsonPathString walk a decoded JSON document along a
+// dot-separated path (e.g. "data.quote.price") and return the leaf as the
+// requested type.
+func jsonPathFloat(doc any, path string) (float64, bool) {
+	v, ok := jsonPathValue(doc, path)
+	if !ok {
+		return 0, false
+	}
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case string:
+		return parseFloat(t), true
+	}
+	return 0, false
+}
+
+func jsonPathString(doc any, path string) (string, bool) {
+	v, ok := jsonPathValue(doc, path)
+	if !ok {
+		return "", false
+	}
+	s, ok := v.(string)
+	return s, ok
+}
+
+func jsonPathValue(doc any, path string) (any, bool) {
+	if path == "" {
+		return nil, false
+	}
+	cur := doc
+	for _, key := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		cur, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
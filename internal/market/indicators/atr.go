@@ -0,0 +1,47 @@
+package indicators
+
+// ATR computes a rolling Average True Range over the last `period` closed
+// bars as a simple moving average of True Range (consistent with the plain
+// rolling averages used elsewhere in this codebase, e.g. the engine's
+// volume-spike ratio).
+type ATR struct {
+	period    int
+	trs       []float64
+	prevClose float64
+	hasPrev   bool
+}
+
+// NewATR builds an ATR over the given period. period <= 0 defaults to 14.
+func NewATR(period int) *ATR {
+	if period <= 0 {
+		period = 14
+	}
+	return &ATR{period: period}
+}
+
+// Add feeds one closed bar into the ATR and returns the current value. The
+// value is 0 until at least one bar has been added.
+func (a *ATR) Add(bar Bar) float64 {
+	tr := bar.Range()
+	if a.hasPrev {
+		if v := bar.High - a.prevClose; v > tr {
+			tr = v
+		}
+		if v := a.prevClose - bar.Low; v > tr {
+			tr = v
+		}
+	}
+	a.prevClose = bar.Close
+	a.hasPrev = true
+
+	a.trs = append(a.trs, tr)
+	if len(a.trs) > a.period {
+		a.trs = a.trs[len(a.trs)-a.period:]
+	}
+
+	var sum float64
+	for _, v := range a.trs {
+		sum += v
+	}
+	return sum / float64(len(a.trs))
+}
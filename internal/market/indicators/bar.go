@@ -0,0 +1,68 @@
+// Package indicators aggregates market.Quote tick streams into OHLC bars
+// and computes rolling technical indicators (ATR, Bollinger bands, NRn) on
+// top of them.
+package indicators
+
+// Bar is one aggregated OHLCV bar.
+type Bar struct {
+	TS     int64
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+// Range returns the bar's high-low range.
+func (b Bar) Range() float64 {
+	return b.High - b.Low
+}
+
+// BarBuilder aggregates a stream of price/volume ticks into fixed-duration
+// OHLC bars.
+type BarBuilder struct {
+	periodSec int64
+	start     int64
+	cur       Bar
+	has       bool
+}
+
+// NewBarBuilder builds a BarBuilder that closes a bar every periodSec
+// seconds. periodSec <= 0 defaults to 300 (5-minute bars).
+func NewBarBuilder(periodSec int64) *BarBuilder {
+	if periodSec <= 0 {
+		periodSec = 300
+	}
+	return &BarBuilder{periodSec: periodSec}
+}
+
+// Add feeds one tick into the builder. It returns the bar that just closed
+// (ok=true) when ts crosses into a new period; the tick that triggered the
+// close starts the next bar.
+func (b *BarBuilder) Add(ts int64, price, volume float64) (Bar, bool) {
+	bucket := ts - (ts % b.periodSec)
+
+	if !b.has {
+		b.start = bucket
+		b.cur = Bar{TS: bucket, Open: price, High: price, Low: price, Close: price, Volume: volume}
+		b.has = true
+		return Bar{}, false
+	}
+
+	if bucket == b.start {
+		if price > b.cur.High {
+			b.cur.High = price
+		}
+		if price < b.cur.Low {
+			b.cur.Low = price
+		}
+		b.cur.Close = price
+		b.cur.Volume += volume
+		return Bar{}, false
+	}
+
+	closed := b.cur
+	b.start = bucket
+	b.cur = Bar{TS: bucket, Open: price, High: price, Low: price, Close: price, Volume: volume}
+	return closed, true
+}
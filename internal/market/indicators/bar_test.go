@@ -0,0 +1,35 @@
+package indicators
+
+import "testing"
+
+func TestBarBuilderAggregatesWithinPeriod(t *testing.T) {
+	b := NewBarBuilder(60)
+
+	if _, closed := b.Add(0, 10, 1); closed {
+		t.Fatalf("first tick should not close a bar")
+	}
+	if _, closed := b.Add(30, 12, 1); closed {
+		t.Fatalf("tick within the same period should not close a bar")
+	}
+	if _, closed := b.Add(59, 8, 1); closed {
+		t.Fatalf("tick within the same period should not close a bar")
+	}
+
+	bar, closed := b.Add(60, 11, 2)
+	if !closed {
+		t.Fatalf("tick crossing into the next period should close the bar")
+	}
+	if bar.Open != 10 || bar.High != 12 || bar.Low != 8 || bar.Close != 8 {
+		t.Fatalf("unexpected closed bar: %+v", bar)
+	}
+	if bar.Volume != 3 {
+		t.Fatalf("expected accumulated volume 3, got %v", bar.Volume)
+	}
+}
+
+func TestBarRange(t *testing.T) {
+	bar := Bar{High: 105, Low: 98}
+	if got := bar.Range(); got != 7 {
+		t.Fatalf("expected range 7, got %v", got)
+	}
+}
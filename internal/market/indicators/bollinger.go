@@ -0,0 +1,53 @@
+package indicators
+
+import "math"
+
+// Bollinger computes rolling Bollinger Bands (SMA mid band +/- k std-devs)
+// over the last `period` closed bars' closing prices.
+type Bollinger struct {
+	period int
+	k      float64
+	closes []float64
+}
+
+// NewBollinger builds a Bollinger calculator. period <= 0 defaults to 20,
+// k <= 0 defaults to 2.0.
+func NewBollinger(period int, k float64) *Bollinger {
+	if period <= 0 {
+		period = 20
+	}
+	if k <= 0 {
+		k = 2.0
+	}
+	return &Bollinger{period: period, k: k}
+}
+
+// Add feeds one closed bar's close price in and returns the mid/upper/lower
+// bands. ok is false until `period` closes have been observed.
+func (b *Bollinger) Add(close float64) (mid, upper, lower float64, ok bool) {
+	b.closes = append(b.closes, close)
+	if len(b.closes) > b.period {
+		b.closes = b.closes[len(b.closes)-b.period:]
+	}
+	if len(b.closes) < b.period {
+		return 0, 0, 0, false
+	}
+
+	var sum float64
+	for _, c := range b.closes {
+		sum += c
+	}
+	mid = sum / float64(len(b.closes))
+
+	var variance float64
+	for _, c := range b.closes {
+		d := c - mid
+		variance += d * d
+	}
+	variance /= float64(len(b.closes))
+	stddev := math.Sqrt(variance)
+
+	upper = mid + b.k*stddev
+	lower = mid - b.k*stddev
+	return mid, upper, lower, true
+}
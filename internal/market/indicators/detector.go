@@ -0,0 +1,75 @@
+package indicators
+
+// NarrowRangeEvidence is the evidence payload the engine attaches to a
+// NARROW_RANGE event when an NRn bar closes.
+type NarrowRangeEvidence struct {
+	N                 int     `json:"n"`
+	Range             float64 `json:"range"`
+	AvgRange20        float64 `json:"avg_range_20"`
+	ATR14             float64 `json:"atr14"`
+	BreakoutLevelUp   float64 `json:"breakout_level_up"`
+	BreakoutLevelDown float64 `json:"breakout_level_down"`
+}
+
+// NarrowRangeDetector aggregates ticks into bars for one symbol and flags
+// NRn compression bars, carrying ATR14 and the trailing average range
+// alongside each detection so callers can scale severity by volatility.
+type NarrowRangeDetector struct {
+	bars        *BarBuilder
+	atr         *ATR
+	nrn         *NRNDetector
+	avgRangeN   int
+	rangeWindow []float64
+}
+
+// NewNarrowRangeDetector builds a detector. n is the NRn lookback (default
+// 4), barPeriodSec is the bar duration (default 300), atrPeriod is the ATR
+// lookback (default 14), avgRangeN is the trailing-average-range lookback
+// (default 20).
+func NewNarrowRangeDetector(n int, barPeriodSec int64, atrPeriod, avgRangeN int) *NarrowRangeDetector {
+	if avgRangeN <= 0 {
+		avgRangeN = 20
+	}
+	return &NarrowRangeDetector{
+		bars:      NewBarBuilder(barPeriodSec),
+		atr:       NewATR(atrPeriod),
+		nrn:       NewNRNDetector(n),
+		avgRangeN: avgRangeN,
+	}
+}
+
+// Add feeds one tick in. It returns the bar that closed and its
+// NarrowRangeEvidence (isNRn=true) whenever that closed bar is an NRn bar;
+// closed=true whenever a bar closed at all, regardless of NRn status.
+func (d *NarrowRangeDetector) Add(ts int64, price, volume float64) (bar Bar, evidence NarrowRangeEvidence, closed bool, isNRn bool) {
+	bar, closed = d.bars.Add(ts, price, volume)
+	if !closed {
+		return Bar{}, NarrowRangeEvidence{}, false, false
+	}
+
+	atr14 := d.atr.Add(bar)
+
+	d.rangeWindow = append(d.rangeWindow, bar.Range())
+	if len(d.rangeWindow) > d.avgRangeN {
+		d.rangeWindow = d.rangeWindow[len(d.rangeWindow)-d.avgRangeN:]
+	}
+	var sum float64
+	for _, r := range d.rangeWindow {
+		sum += r
+	}
+	avgRange := sum / float64(len(d.rangeWindow))
+
+	rng, nrn := d.nrn.Add(bar)
+	if !nrn {
+		return bar, NarrowRangeEvidence{}, true, false
+	}
+
+	return bar, NarrowRangeEvidence{
+		N:                 d.nrn.n,
+		Range:             rng,
+		AvgRange20:        avgRange,
+		ATR14:             atr14,
+		BreakoutLevelUp:   bar.High,
+		BreakoutLevelDown: bar.Low,
+	}, true, true
+}
@@ -0,0 +1,43 @@
+package indicators
+
+import "testing"
+
+func TestNarrowRangeDetectorEmitsEvidenceOnNRnClose(t *testing.T) {
+	d := NewNarrowRangeDetector(4, 60, 14, 20)
+
+	// Three wide bars, then a narrow closing bar; each period is 60s.
+	ticks := []struct {
+		ts    int64
+		price float64
+	}{
+		{0, 100}, {30, 110}, {59, 90}, // bar0: range 20
+		{60, 92}, {90, 98}, {119, 80}, // bar1: range 18
+		{120, 82}, {150, 86}, {179, 75}, // bar2: range 11
+		{180, 76}, {210, 77}, // bar3 open
+		{240, 78}, // closes bar3: range 1, smallest of last 4
+	}
+
+	var gotEvidence bool
+	for _, tk := range ticks {
+		_, ev, closed, isNRn := d.Add(tk.ts, tk.price, 1)
+		if closed && isNRn {
+			gotEvidence = true
+			if ev.N != 4 {
+				t.Fatalf("expected N=4, got %d", ev.N)
+			}
+			if ev.Range <= 0 {
+				t.Fatalf("expected positive range, got %v", ev.Range)
+			}
+			if ev.AvgRange20 <= 0 {
+				t.Fatalf("expected positive avg_range_20, got %v", ev.AvgRange20)
+			}
+			if ev.BreakoutLevelUp < ev.BreakoutLevelDown {
+				t.Fatalf("expected breakout up >= down, got up=%v down=%v", ev.BreakoutLevelUp, ev.BreakoutLevelDown)
+			}
+		}
+	}
+
+	if !gotEvidence {
+		t.Fatalf("expected at least one NRn bar to be detected in the synthetic sequence")
+	}
+}
@@ -0,0 +1,38 @@
+package indicators
+
+// NRNDetector flags a closed bar as Narrow-Range-N (NRn) when its range is
+// the smallest among the last n closed bars (Toby Crabel's NR pattern,
+// typically n=4 or n=7).
+type NRNDetector struct {
+	n      int
+	ranges []float64
+}
+
+// NewNRNDetector builds an NRNDetector over the given n. n <= 0 defaults
+// to 4.
+func NewNRNDetector(n int) *NRNDetector {
+	if n <= 0 {
+		n = 4
+	}
+	return &NRNDetector{n: n}
+}
+
+// Add feeds one closed bar's range in and reports whether it is an NRn bar.
+// isNRn is always false until n bars have been observed.
+func (d *NRNDetector) Add(bar Bar) (rng float64, isNRn bool) {
+	rng = bar.Range()
+	d.ranges = append(d.ranges, rng)
+	if len(d.ranges) > d.n {
+		d.ranges = d.ranges[len(d.ranges)-d.n:]
+	}
+	if len(d.ranges) < d.n {
+		return rng, false
+	}
+
+	for _, r := range d.ranges[:len(d.ranges)-1] {
+		if r < rng {
+			return rng, false
+		}
+	}
+	return rng, true
+}
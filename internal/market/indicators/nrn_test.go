@@ -0,0 +1,52 @@
+package indicators
+
+import "testing"
+
+func TestNRNDetectorFlagsSmallestRangeBar(t *testing.T) {
+	d := NewNRNDetector(4)
+
+	bars := []Bar{
+		{High: 110, Low: 100}, // range 10
+		{High: 108, Low: 100}, // range 8
+		{High: 106, Low: 100}, // range 6
+		{High: 103, Low: 100}, // range 3, smallest of last 4
+	}
+
+	var lastNRn bool
+	var lastRange float64
+	for _, bar := range bars {
+		lastRange, lastNRn = d.Add(bar)
+	}
+
+	if !lastNRn {
+		t.Fatalf("expected last bar (smallest range) to be flagged as NRn")
+	}
+	if lastRange != 3 {
+		t.Fatalf("expected range 3, got %v", lastRange)
+	}
+}
+
+func TestNRNDetectorDoesNotFlagBeforeWarmup(t *testing.T) {
+	d := NewNRNDetector(4)
+	_, isNRn := d.Add(Bar{High: 101, Low: 100})
+	if isNRn {
+		t.Fatalf("should not flag NRn before n bars have been observed")
+	}
+}
+
+func TestNRNDetectorRejectsNonSmallestBar(t *testing.T) {
+	d := NewNRNDetector(4)
+	bars := []Bar{
+		{High: 103, Low: 100}, // range 3
+		{High: 105, Low: 100}, // range 5
+		{High: 104, Low: 100}, // range 4
+		{High: 108, Low: 100}, // range 8, not smallest
+	}
+	var lastNRn bool
+	for _, bar := range bars {
+		_, lastNRn = d.Add(bar)
+	}
+	if lastNRn {
+		t.Fatalf("largest-range bar should not be flagged as NRn")
+	}
+}
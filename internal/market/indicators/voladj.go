@@ -0,0 +1,86 @@
+package indicators
+
+import "math"
+
+// volAdjEpsilon is the minimum sample stddev VolAdjDetector requires before
+// it will score a return; below this the window is treated as degenerate
+// (e.g. a flat-lined feed) rather than producing a wild z-score.
+const volAdjEpsilon = 1e-8
+
+// VolAdjDetector maintains a rolling window of log-returns for one symbol
+// and scores each new return as a z-score against that window's own mean
+// and sample stddev, computed in O(1) per update via a running sum and
+// sum-of-squares over a ring buffer. This lets a caller flag drops that are
+// unusual for *this* symbol's own volatility regime, rather than against a
+// single global percentage threshold.
+type VolAdjDetector struct {
+	window    int
+	lastPrice float64
+	hasLast   bool
+
+	returns []float64
+	pos     int
+	sum     float64
+	sumSq   float64
+}
+
+// NewVolAdjDetector builds a detector over the last windowPoints log-returns
+// (default 60 if windowPoints <= 1).
+func NewVolAdjDetector(windowPoints int) *VolAdjDetector {
+	if windowPoints <= 1 {
+		windowPoints = 60
+	}
+	return &VolAdjDetector{window: windowPoints}
+}
+
+// Add feeds one price in and returns the z-score of its log-return against
+// the rolling window, and whether the window is full enough (and not
+// degenerate) for that z-score to be meaningful. The first call only seeds
+// lastPrice and always reports ready=false.
+func (d *VolAdjDetector) Add(price float64) (z float64, ready bool) {
+	if price <= 0 {
+		return 0, false
+	}
+	if !d.hasLast {
+		d.lastPrice = price
+		d.hasLast = true
+		return 0, false
+	}
+
+	r := math.Log(price / d.lastPrice)
+	d.lastPrice = price
+	d.push(r)
+
+	n := len(d.returns)
+	if n < d.window || n < 2 {
+		return 0, false
+	}
+
+	mean := d.sum / float64(n)
+	variance := (d.sumSq - float64(n)*mean*mean) / float64(n-1)
+	if variance < 0 {
+		variance = 0
+	}
+	stddev := math.Sqrt(variance)
+	if stddev <= volAdjEpsilon {
+		return 0, false
+	}
+	return (r - mean) / stddev, true
+}
+
+// push appends r to the ring buffer, evicting the oldest return once the
+// buffer is full so sum/sumSq always describe exactly the last `window`
+// returns.
+func (d *VolAdjDetector) push(r float64) {
+	if len(d.returns) < d.window {
+		d.returns = append(d.returns, r)
+		d.sum += r
+		d.sumSq += r * r
+		return
+	}
+	old := d.returns[d.pos]
+	d.sum += r - old
+	d.sumSq += r*r - old*old
+	d.returns[d.pos] = r
+	d.pos = (d.pos + 1) % d.window
+}
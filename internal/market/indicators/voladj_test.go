@@ -0,0 +1,65 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVolAdjDetectorNotReadyBeforeWindowFull(t *testing.T) {
+	d := NewVolAdjDetector(4)
+	prices := []float64{100, 101, 102, 103}
+	var ready bool
+	for _, p := range prices {
+		_, ready = d.Add(p)
+	}
+	if ready {
+		t.Fatalf("should not be ready before window*+1 prices have been observed")
+	}
+}
+
+func TestVolAdjDetectorFlagsOutlierReturn(t *testing.T) {
+	d := NewVolAdjDetector(4)
+	for _, p := range []float64{100, 100.1, 99.9, 100.1, 99.9} {
+		d.Add(p)
+	}
+	// A sharp drop after a tight, low-volatility window should score a
+	// strongly negative z.
+	z, ready := d.Add(95)
+	if !ready {
+		t.Fatalf("expected detector to be ready once the window is full")
+	}
+	if z >= -1 {
+		t.Fatalf("expected a strongly negative z-score for an outlier drop, got %v", z)
+	}
+}
+
+func TestVolAdjDetectorGuardsZeroStddev(t *testing.T) {
+	d := NewVolAdjDetector(4)
+	for i := 0; i < 6; i++ {
+		_, ready := d.Add(100)
+		if ready {
+			t.Fatalf("flat prices should never produce a stddev above epsilon")
+		}
+	}
+}
+
+func TestVolAdjDetectorWindowIsRolling(t *testing.T) {
+	d := NewVolAdjDetector(3)
+	for _, p := range []float64{100, 101, 102, 103} {
+		d.Add(p)
+	}
+	if len(d.returns) != 3 {
+		t.Fatalf("expected ring buffer capped at window size 3, got %d", len(d.returns))
+	}
+
+	want := math.Log(103.0 / 102.0)
+	found := false
+	for _, r := range d.returns {
+		if math.Abs(r-want) < 1e-12 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected most recent return %v to be present in rolling window %v", want, d.returns)
+	}
+}
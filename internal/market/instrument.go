@@ -0,0 +1,123 @@
+package market
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+)
+
+// Instrument carries the contract metadata needed to turn a model- or
+// human-proposed price/quantity into something a broker will actually
+// accept: the minimum price increment, the minimum (and only legal)
+// order size increment, and enough context to interpret the two
+// (currency, underlying index, and delivery date for futures).
+type Instrument struct {
+	Symbol          string  `json:"symbol"`
+	PriceTick       float64 `json:"price_tick"`
+	LotSize         float64 `json:"lot_size"`
+	ContractValue   float64 `json:"contract_value,omitempty"`
+	QuoteCurrency   string  `json:"quote_currency"`
+	UnderlyingIndex string  `json:"underlying_index,omitempty"`
+	Delivery        string  `json:"delivery,omitempty"` // futures delivery date, YYYY-MM-DD; empty for spot/equity
+}
+
+// InstrumentProvider looks up one symbol's contract metadata from an
+// external catalog (exchange reference data, a broker's instrument
+// endpoint, etc). Implementations should return an error rather than a
+// zero-value Instrument when the symbol is unknown, so InstrumentService
+// can fall back to its built-in defaults.
+type InstrumentProvider interface {
+	GetInstrument(ctx context.Context, symbol string) (Instrument, error)
+}
+
+// InstrumentService loads and caches instrument metadata on top of an
+// optional InstrumentProvider. A nil provider (or a provider that doesn't
+// know about a symbol) is not an error: the service falls back to
+// exchange-level defaults derived from ParseSymbol, the same way the rest
+// of this package treats a bare symbol prefix as enough to route a quote
+// request.
+type InstrumentService struct {
+	provider InstrumentProvider
+
+	mu    sync.Mutex
+	cache map[string]Instrument
+}
+
+func NewInstrumentService(provider InstrumentProvider) *InstrumentService {
+	return &InstrumentService{
+		provider: provider,
+		cache:    make(map[string]Instrument),
+	}
+}
+
+// Get returns the cached or freshly loaded Instrument for symbol, falling
+// back to DefaultInstrument(symbol) if no provider is configured or the
+// provider doesn't recognize it.
+func (s *InstrumentService) Get(symbol string) Instrument {
+	key := strings.ToLower(symbol)
+
+	s.mu.Lock()
+	if inst, ok := s.cache[key]; ok {
+		s.mu.Unlock()
+		return inst
+	}
+	s.mu.Unlock()
+
+	inst := DefaultInstrument(symbol)
+	if s.provider != nil {
+		if loaded, err := s.provider.GetInstrument(context.Background(), symbol); err == nil {
+			inst = loaded
+		}
+	}
+
+	s.mu.Lock()
+	s.cache[key] = inst
+	s.mu.Unlock()
+	return inst
+}
+
+// RoundPrice snaps px to the symbol's legal price tick. Symbols with no
+// known tick size (PriceTick <= 0) are returned unchanged.
+func (s *InstrumentService) RoundPrice(symbol string, px float64) float64 {
+	inst := s.Get(symbol)
+	if inst.PriceTick <= 0 {
+		return px
+	}
+	return math.Round(px/inst.PriceTick) * inst.PriceTick
+}
+
+// RoundQty snaps qty to the symbol's legal lot size. Symbols with no known
+// lot size (LotSize <= 0) are returned unchanged.
+func (s *InstrumentService) RoundQty(symbol string, qty float64) float64 {
+	inst := s.Get(symbol)
+	if inst.LotSize <= 0 {
+		return qty
+	}
+	return math.Round(qty/inst.LotSize) * inst.LotSize
+}
+
+// DefaultInstrument derives tick size, lot size, and quote currency from
+// a symbol's exchange prefix when no richer catalog entry is available.
+// These match the conventions most A-share/HK/US-ADR brokers enforce:
+// 0.01 ticks everywhere, a 100-share board lot for A-shares and HK,
+// single-share increments for US equities.
+func DefaultInstrument(symbol string) Instrument {
+	inst := Instrument{Symbol: symbol, PriceTick: 0.01, LotSize: 1, QuoteCurrency: "USD"}
+	ex, _, err := ParseSymbol(symbol)
+	if err != nil {
+		return inst
+	}
+	switch ex {
+	case ExchangeSH, ExchangeSZ, ExchangeBJ:
+		inst.LotSize = 100
+		inst.QuoteCurrency = "CNY"
+	case ExchangeHK:
+		inst.LotSize = 100
+		inst.QuoteCurrency = "HKD"
+	case ExchangeUS:
+		inst.LotSize = 1
+		inst.QuoteCurrency = "USD"
+	}
+	return inst
+}
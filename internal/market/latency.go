@@ -0,0 +1,58 @@
+package market
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencyTracker keeps the most recent call latencies for one provider and
+// computes a rolling p95 used by the weighted selector.
+type latencyTracker struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	size    int
+	next    int
+	full    bool
+}
+
+func newLatencyTracker(size int) *latencyTracker {
+	if size <= 0 {
+		size = 64
+	}
+	return &latencyTracker{samples: make([]time.Duration, size), size: size}
+}
+
+func (t *latencyTracker) observe(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.samples[t.next] = d
+	t.next = (t.next + 1) % t.size
+	if t.next == 0 {
+		t.full = true
+	}
+}
+
+// p95 returns the rolling 95th-percentile latency, or 0 if no samples have
+// been observed yet.
+func (t *latencyTracker) p95() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := t.next
+	if t.full {
+		n = t.size
+	}
+	if n == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, n)
+	copy(sorted, t.samples[:n])
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	idx := int(float64(n) * 0.95)
+	if idx >= n {
+		idx = n - 1
+	}
+	return sorted[idx]
+}
@@ -3,24 +3,207 @@ package market
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"ai-trading-assistant/internal/metrics"
+)
+
+// Strategy selects how MultiProvider fans its providers out for a single
+// GetQuotes call.
+type Strategy int
+
+const (
+	// StrategyFailover tries providers one at a time, in weighted order,
+	// and returns the first non-empty success. This is the original
+	// behavior and remains the default.
+	StrategyFailover Strategy = iota
+	// StrategyRace issues calls to all providers in parallel and returns
+	// the first successful non-empty result, cancelling the rest.
+	StrategyRace
+	// StrategyQuorum calls all providers in parallel and, per symbol,
+	// returns the provider quote closest to the median price (or the
+	// freshest, per TieBreaker), tagging outliers beyond the tolerance.
+	// The K-of-N agreement count is reported in QuoteDiagnostics; a symbol
+	// is returned even when fewer than K providers agree.
+	StrategyQuorum
+	// StrategyHedged starts the primary (weighted-first) provider, then
+	// fires a secondary after hedgeDelay if the primary hasn't returned
+	// yet, returning whichever completes first.
+	StrategyHedged
+)
+
+// TieBreaker selects how GetQuotesWithDiagnostics picks the winning quote
+// for a symbol once every provider that answered has been collected.
+type TieBreaker int
+
+const (
+	// TieBreakerConsensus picks the quote closest to the cross-provider
+	// median price. This is the original behavior and remains the
+	// default.
+	TieBreakerConsensus TieBreaker = iota
+	// TieBreakerFreshest picks the quote with the largest TS (most
+	// recently reported), regardless of how it compares to the median.
+	// Outlier tagging in QuoteDiagnostics still uses the median, so a
+	// freshest pick that's also a price outlier is still visible to
+	// callers.
+	TieBreakerFreshest
 )
 
+// MultiProviderConfig tunes MultiProvider's fan-out strategy, circuit
+// breakers, and quorum tolerance. The zero value is a sane, conservative
+// default (sequential failover).
+type MultiProviderConfig struct {
+	Strategy Strategy
+
+	// Quorum tuning. QuorumK defaults to a simple majority of the
+	// configured providers; QuorumTolerancePct defaults to 0.5%.
+	QuorumK            int
+	QuorumTolerancePct float64
+
+	// TieBreaker picks the winning quote once quorum/diagnostics merging
+	// has collected every provider's answer for a symbol. Defaults to
+	// TieBreakerConsensus.
+	TieBreaker TieBreaker
+
+	// HedgeDelay is how long StrategyHedged waits for the primary before
+	// firing the secondary. Defaults to 200ms.
+	HedgeDelay time.Duration
+
+	// Circuit breaker tuning: a provider opens after BreakerFailThreshold
+	// consecutive failures and stays open for BreakerOpenFor. Defaults to
+	// 5 failures / 30s.
+	BreakerFailThreshold int
+	BreakerOpenFor       time.Duration
+
+	// Observer, if set, receives a latency sample for every per-provider
+	// call (success or failure).
+	Observer Observer
+}
+
 type MultiProvider struct {
 	providers []MarketProvider
+	names     []string
+	cfg       MultiProviderConfig
+	breakers  []*circuitBreaker
+	latencies []*latencyTracker
 }
 
+// NewMultiProvider builds a MultiProvider using the original sequential
+// failover strategy with default circuit-breaker settings.
 func NewMultiProvider(providers ...MarketProvider) *MultiProvider {
-	return &MultiProvider{providers: providers}
+	return NewMultiProviderWithConfig(MultiProviderConfig{}, providers...)
 }
 
+// NewMultiProviderWithConfig builds a MultiProvider with an explicit
+// strategy, quorum tolerance, hedge delay, circuit breaker thresholds, and
+// latency Observer.
+func NewMultiProviderWithConfig(cfg MultiProviderConfig, providers ...MarketProvider) *MultiProvider {
+	if cfg.QuorumK <= 0 {
+		cfg.QuorumK = len(providers)/2 + 1
+	}
+	if cfg.QuorumTolerancePct <= 0 {
+		cfg.QuorumTolerancePct = 0.5
+	}
+	if cfg.HedgeDelay <= 0 {
+		cfg.HedgeDelay = 200 * time.Millisecond
+	}
+	if cfg.BreakerFailThreshold <= 0 {
+		cfg.BreakerFailThreshold = 5
+	}
+	if cfg.BreakerOpenFor <= 0 {
+		cfg.BreakerOpenFor = 30 * time.Second
+	}
+
+	names := make([]string, len(providers))
+	breakers := make([]*circuitBreaker, len(providers))
+	latencies := make([]*latencyTracker, len(providers))
+	for i, p := range providers {
+		names[i] = providerName(p, i)
+		breakers[i] = newCircuitBreaker(cfg.BreakerFailThreshold, cfg.BreakerOpenFor)
+		latencies[i] = newLatencyTracker(64)
+	}
+
+	return &MultiProvider{
+		providers: providers,
+		names:     names,
+		cfg:       cfg,
+		breakers:  breakers,
+		latencies: latencies,
+	}
+}
+
+func providerName(p MarketProvider, idx int) string {
+	return fmt.Sprintf("%T#%d", p, idx)
+}
+
+// GetQuotes fans out to the configured providers per m.cfg.Strategy. For
+// StrategyQuorum, use GetQuotesWithDiagnostics if per-symbol agreement
+// detail is needed; GetQuotes itself degrades to "median wins" silently.
 func (m *MultiProvider) GetQuotes(ctx context.Context, symbols []string) ([]Quote, string, error) {
 	if len(m.providers) == 0 {
 		return nil, "", fmt.Errorf("no market providers configured")
 	}
+	switch m.cfg.Strategy {
+	case StrategyRace:
+		return m.getQuotesRace(ctx, symbols)
+	case StrategyQuorum:
+		quotes, _, err := m.GetQuotesWithDiagnostics(ctx, symbols)
+		return quotes, "quorum", err
+	case StrategyHedged:
+		return m.getQuotesHedged(ctx, symbols)
+	default:
+		return m.getQuotesFailover(ctx, symbols)
+	}
+}
+
+// orderedIndices returns provider indices sorted by ascending rolling p95
+// latency (providers with no samples yet sort first, so they get tried).
+func (m *MultiProvider) orderedIndices() []int {
+	idx := make([]int, len(m.providers))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		return m.latencies[idx[a]].p95() < m.latencies[idx[b]].p95()
+	})
+	return idx
+}
+
+func (m *MultiProvider) callProvider(ctx context.Context, i int, symbols []string) ([]Quote, string, error) {
+	if !m.breakers[i].allow() {
+		return nil, "", fmt.Errorf("%s: circuit open", m.names[i])
+	}
+	start := time.Now()
+	quotes, source, err := m.providers[i].GetQuotes(ctx, symbols)
+	elapsed := time.Since(start)
+
+	m.latencies[i].observe(elapsed)
+	metrics.MarketProviderRequestDuration.WithLabelValues(m.names[i]).Observe(elapsed.Seconds())
+	if m.cfg.Observer != nil {
+		m.cfg.Observer.ObserveLatency(m.names[i], elapsed, err)
+	}
+	if err != nil || len(quotes) == 0 {
+		if err == nil {
+			err = fmt.Errorf("%s: empty result", m.names[i])
+		}
+		metrics.MarketProviderErrorsTotal.WithLabelValues(m.names[i]).Inc()
+		m.breakers[i].recordFailure(err)
+		return nil, "", err
+	}
+	m.breakers[i].recordSuccess()
+	for idx := range quotes {
+		quotes[idx].Source = m.names[i]
+	}
+	return quotes, source, nil
+}
+
+func (m *MultiProvider) getQuotesFailover(ctx context.Context, symbols []string) ([]Quote, string, error) {
 	var lastErr error
-	for _, p := range m.providers {
-		quotes, source, err := p.GetQuotes(ctx, symbols)
-		if err == nil && len(quotes) > 0 {
+	for _, i := range m.orderedIndices() {
+		quotes, source, err := m.callProvider(ctx, i, symbols)
+		if err == nil {
 			return quotes, source, nil
 		}
 		lastErr = err
@@ -30,3 +213,178 @@ func (m *MultiProvider) GetQuotes(ctx context.Context, symbols []string) ([]Quot
 	}
 	return nil, "", lastErr
 }
+
+type raceResult struct {
+	quotes []Quote
+	source string
+	err    error
+}
+
+func (m *MultiProvider) getQuotesRace(ctx context.Context, symbols []string) ([]Quote, string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan raceResult, len(m.providers))
+	for _, i := range m.orderedIndices() {
+		i := i
+		go func() {
+			quotes, source, err := m.callProvider(ctx, i, symbols)
+			results <- raceResult{quotes: quotes, source: source, err: err}
+		}()
+	}
+
+	var lastErr error
+	for range m.providers {
+		r := <-results
+		if r.err == nil {
+			return r.quotes, r.source, nil
+		}
+		lastErr = r.err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("all providers failed")
+	}
+	return nil, "", lastErr
+}
+
+func (m *MultiProvider) getQuotesHedged(ctx context.Context, symbols []string) ([]Quote, string, error) {
+	order := m.orderedIndices()
+	if len(order) == 1 {
+		return m.callProvider(ctx, order[0], symbols)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan raceResult, len(order))
+	go func() {
+		quotes, source, err := m.callProvider(ctx, order[0], symbols)
+		results <- raceResult{quotes: quotes, source: source, err: err}
+	}()
+
+	timer := time.NewTimer(m.cfg.HedgeDelay)
+	defer timer.Stop()
+
+	pending := 1
+	hedgeFired := false
+	var lastErr error
+	for pending > 0 {
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				return r.quotes, r.source, nil
+			}
+			lastErr = r.err
+		case <-timer.C:
+			if !hedgeFired && len(order) > 1 {
+				hedgeFired = true
+				pending++
+				go func() {
+					quotes, source, err := m.callProvider(ctx, order[1], symbols)
+					results <- raceResult{quotes: quotes, source: source, err: err}
+				}()
+			}
+		}
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("all providers failed")
+	}
+	return nil, "", lastErr
+}
+
+// GetQuotesWithDiagnostics runs the quorum strategy regardless of
+// m.cfg.Strategy: it calls every healthy provider in parallel, merges
+// same-symbol quotes by median with outlier tagging, and reports per-symbol
+// QuoteDiagnostics. Symbols only one provider supports still return (with
+// Total=1, Agreed=1) rather than failing the whole batch.
+func (m *MultiProvider) GetQuotesWithDiagnostics(ctx context.Context, symbols []string) ([]Quote, []QuoteDiagnostics, error) {
+	if len(m.providers) == 0 {
+		return nil, nil, fmt.Errorf("no market providers configured")
+	}
+
+	type providerQuotes struct {
+		name   string
+		quotes []Quote
+	}
+	var mu sync.Mutex
+	var all []providerQuotes
+	var wg sync.WaitGroup
+
+	for _, i := range m.orderedIndices() {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			quotes, _, err := m.callProvider(ctx, i, symbols)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			all = append(all, providerQuotes{name: m.names[i], quotes: quotes})
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if len(all) == 0 {
+		return nil, nil, fmt.Errorf("all providers failed")
+	}
+
+	bySymbol := make(map[string][]Quote)
+	namesBySymbol := make(map[string][]string)
+	for _, pq := range all {
+		for _, q := range pq.quotes {
+			bySymbol[q.Symbol] = append(bySymbol[q.Symbol], q)
+			namesBySymbol[q.Symbol] = append(namesBySymbol[q.Symbol], pq.name)
+		}
+	}
+
+	var out []Quote
+	var diags []QuoteDiagnostics
+	for _, symbol := range symbols {
+		quotes := bySymbol[symbol]
+		if len(quotes) == 0 {
+			continue
+		}
+		best, diag, _ := quorumResolve(symbol, quotes, namesBySymbol[symbol], m.cfg.QuorumK, m.cfg.QuorumTolerancePct, m.cfg.TieBreaker)
+		out = append(out, best)
+		diags = append(diags, diag)
+	}
+	if len(out) == 0 {
+		return nil, nil, fmt.Errorf("no symbols resolved by any provider")
+	}
+	return out, diags, nil
+}
+
+// ProviderStatus reports one provider's health for the /api/v1/providers
+// endpoint: whether its circuit breaker is open, its last error and last
+// success/failure times, and its rolling p95 latency.
+type ProviderStatus struct {
+	Name         string    `json:"name"`
+	CircuitOpen  bool      `json:"circuit_open"`
+	LastError    string    `json:"last_error,omitempty"`
+	LastSuccess  time.Time `json:"last_success,omitempty"`
+	LastFailure  time.Time `json:"last_failure,omitempty"`
+	P95LatencyMs float64   `json:"p95_latency_ms"`
+}
+
+// Status reports the current health of every configured provider.
+func (m *MultiProvider) Status() []ProviderStatus {
+	out := make([]ProviderStatus, len(m.providers))
+	for i := range m.providers {
+		open, lastErr, lastSuccess, lastFailure := m.breakers[i].snapshot()
+		status := ProviderStatus{
+			Name:         m.names[i],
+			CircuitOpen:  open,
+			LastSuccess:  lastSuccess,
+			LastFailure:  lastFailure,
+			P95LatencyMs: float64(m.latencies[i].p95().Microseconds()) / 1000,
+		}
+		if lastErr != nil {
+			status.LastError = lastErr.Error()
+		}
+		out[i] = status
+	}
+	return out
+}
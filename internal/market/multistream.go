@@ -0,0 +1,249 @@
+package market
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// QuoteSink receives every tick a MultiStreamProvider ingests (from a live
+// stream or its fallback poll), in addition to the per-symbol ring buffer
+// it keeps for GetLatest. market.Service implements QuoteSink so streamed
+// quotes can drive the engine/store/broker pipeline the same way
+// PollAndStore drives it from polled ones.
+type QuoteSink interface {
+	IngestQuote(q Quote)
+}
+
+// MultiStreamConfig tunes fan-out behaviour for MultiStreamProvider.
+type MultiStreamConfig struct {
+	RingSize      int
+	ReconnectBase time.Duration
+	ReconnectMax  time.Duration
+	FallbackPoll  time.Duration
+	StaleAfter    time.Duration
+
+	// Sink, if set, is called with every ingested tick (stream or
+	// fallback-poll) in addition to the ring buffer.
+	Sink QuoteSink
+}
+
+// MultiStreamProvider fans a symbol subscription out across a list of
+// StreamingProvider sources, deduplicates ticks per symbol, and falls back
+// to polling a MarketProvider for any symbol whose streams have gone quiet.
+type MultiStreamProvider struct {
+	providers []StreamingProvider
+	fallback  MarketProvider
+	cfg       MultiStreamConfig
+
+	mu       sync.Mutex
+	rings    map[string]*quoteRing
+	lastSeen map[string]time.Time
+	cancel   context.CancelFunc
+}
+
+func NewMultiStreamProvider(fallback MarketProvider, cfg MultiStreamConfig, providers ...StreamingProvider) *MultiStreamProvider {
+	if cfg.RingSize <= 0 {
+		cfg.RingSize = 64
+	}
+	if cfg.ReconnectBase <= 0 {
+		cfg.ReconnectBase = time.Second
+	}
+	if cfg.ReconnectMax <= 0 {
+		cfg.ReconnectMax = 30 * time.Second
+	}
+	if cfg.FallbackPoll <= 0 {
+		cfg.FallbackPoll = 5 * time.Second
+	}
+	if cfg.StaleAfter <= 0 {
+		cfg.StaleAfter = 15 * time.Second
+	}
+	return &MultiStreamProvider{
+		providers: providers,
+		fallback:  fallback,
+		cfg:       cfg,
+		rings:     make(map[string]*quoteRing),
+		lastSeen:  make(map[string]time.Time),
+	}
+}
+
+// Run subscribes every provider to symbols and blocks, fanning ticks into
+// the per-symbol ring buffers, until ctx is cancelled. Callers should run it
+// in a goroutine, the same way Service.PollLoop is run today.
+func (m *MultiStreamProvider) Run(ctx context.Context, symbols []string) {
+	ctx, cancel := context.WithCancel(ctx)
+	m.mu.Lock()
+	m.cancel = cancel
+	m.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, p := range m.providers {
+		wg.Add(1)
+		go func(p StreamingProvider) {
+			defer wg.Done()
+			m.runProvider(ctx, p, symbols)
+		}(p)
+	}
+	if m.fallback != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.runFallbackPoll(ctx, symbols)
+		}()
+	}
+	wg.Wait()
+}
+
+// Resubscribe diffs against the running subscription by simply cancelling
+// the current fan-out and restarting it with the new symbol list.
+func (m *MultiStreamProvider) Resubscribe(ctx context.Context, symbols []string) {
+	m.mu.Lock()
+	cancel := m.cancel
+	m.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	go m.Run(ctx, symbols)
+}
+
+func (m *MultiStreamProvider) runProvider(ctx context.Context, p StreamingProvider, symbols []string) {
+	backoff := m.cfg.ReconnectBase
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		ch, err := p.Subscribe(ctx, symbols)
+		if err != nil {
+			log.Printf("market stream %s subscribe error: %v", p.Name(), err)
+			time.Sleep(withJitter(backoff))
+			backoff = nextBackoff(backoff, m.cfg.ReconnectMax)
+			continue
+		}
+		backoff = m.cfg.ReconnectBase
+		m.drain(ctx, ch)
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			log.Printf("market stream %s disconnected, reconnecting", p.Name())
+		}
+	}
+}
+
+func (m *MultiStreamProvider) drain(ctx context.Context, ch <-chan Quote) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case q, ok := <-ch:
+			if !ok {
+				return
+			}
+			m.ingest(q)
+		}
+	}
+}
+
+func (m *MultiStreamProvider) ingest(q Quote) {
+	sym := strings.ToLower(q.Symbol)
+	if sym == "" {
+		return
+	}
+	if q.TS == 0 {
+		q.TS = time.Now().Unix()
+	}
+	m.mu.Lock()
+	ring, ok := m.rings[sym]
+	if !ok {
+		ring = newQuoteRing(m.cfg.RingSize)
+		m.rings[sym] = ring
+	}
+	m.lastSeen[sym] = time.Now()
+	m.mu.Unlock()
+
+	if prev, ok := ring.latest(); ok && prev.TS == q.TS && prev.Price == q.Price {
+		return // duplicate tick, likely echoed by more than one provider
+	}
+	ring.push(q)
+	if m.cfg.Sink != nil {
+		m.cfg.Sink.IngestQuote(q)
+	}
+}
+
+// GetLatest returns the freshest push quote seen for symbol, if any, so
+// callers like the risk agent and event detectors can drive off push
+// updates instead of periodic polling.
+func (m *MultiStreamProvider) GetLatest(symbol string) (Quote, bool) {
+	m.mu.Lock()
+	ring, ok := m.rings[strings.ToLower(symbol)]
+	m.mu.Unlock()
+	if !ok {
+		return Quote{}, false
+	}
+	return ring.latest()
+}
+
+func (m *MultiStreamProvider) runFallbackPoll(ctx context.Context, symbols []string) {
+	ticker := time.NewTicker(m.cfg.FallbackPoll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stale := m.staleSymbols(symbols)
+			if len(stale) == 0 {
+				continue
+			}
+			quotes, _, err := m.fallback.GetQuotes(ctx, stale)
+			if err != nil {
+				log.Printf("market stream fallback poll error: %v", err)
+				continue
+			}
+			for _, q := range quotes {
+				m.ingest(q)
+			}
+		}
+	}
+}
+
+func (m *MultiStreamProvider) staleSymbols(symbols []string) []string {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]string, 0, len(symbols))
+	for _, sym := range symbols {
+		key := strings.ToLower(sym)
+		last, ok := m.lastSeen[key]
+		if !ok || now.Sub(last) > m.cfg.StaleAfter {
+			out = append(out, sym)
+		}
+	}
+	return out
+}
+
+func nextBackoff(cur, max time.Duration) time.Duration {
+	next := cur * 2
+	if next > max {
+		return max
+	}
+	return next
+}
+
+// withJitter randomizes d by up to ±25% so many reconnecting subscribers
+// don't all retry in lockstep after a shared upstream blip.
+func withJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	half := int64(d) / 2
+	if half <= 0 {
+		return d
+	}
+	return d - d/4 + time.Duration(rand.Int63n(half))
+}
@@ -0,0 +1,10 @@
+package market
+
+import "time"
+
+// Observer receives per-call latency/outcome samples from MultiProvider so
+// callers can export them (e.g. as Prometheus histograms) without
+// MultiProvider depending on any metrics library.
+type Observer interface {
+	ObserveLatency(provider string, d time.Duration, err error)
+}
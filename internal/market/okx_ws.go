@@ -0,0 +1,127 @@
+package market
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// OKXWSProvider streams last-price tickers from OKX's public WebSocket
+// gateway. Set Futures to subscribe against perpetual SWAP instruments
+// instead of spot.
+type OKXWSProvider struct {
+	Futures bool
+}
+
+func NewOKXWSProvider(futures bool) *OKXWSProvider {
+	return &OKXWSProvider{Futures: futures}
+}
+
+func (p *OKXWSProvider) Name() string {
+	if p.Futures {
+		return "okx-futures-ws"
+	}
+	return "okx-spot-ws"
+}
+
+func (p *OKXWSProvider) Subscribe(ctx context.Context, symbols []string) (<-chan Quote, error) {
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("symbols is empty")
+	}
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, "wss://ws.okx.com:8443/ws/v5/public", nil)
+	if err != nil {
+		return nil, fmt.Errorf("dial okx ws: %w", err)
+	}
+
+	args := make([]map[string]string, 0, len(symbols))
+	for _, s := range symbols {
+		args = append(args, map[string]string{"channel": "tickers", "instId": p.instID(s)})
+	}
+	sub := map[string]any{"op": "subscribe", "args": args}
+	if err := conn.WriteJSON(sub); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("subscribe okx ws: %w", err)
+	}
+
+	out := make(chan Quote, 256)
+	startWSHeartbeat(ctx, conn)
+	go func() {
+		defer close(out)
+		defer conn.Close()
+		go closeOnDone(ctx, conn)
+		for {
+			_, raw, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if q, ok := parseOKXTicker(raw); ok {
+				select {
+				case out <- q:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (p *OKXWSProvider) instID(symbol string) string {
+	s := strings.ToUpper(strings.TrimSpace(symbol))
+	if strings.Contains(s, "-") {
+		return s
+	}
+	if !strings.HasSuffix(s, "USDT") {
+		return s
+	}
+	base := strings.TrimSuffix(s, "USDT") + "-USDT"
+	if p.Futures {
+		return base + "-SWAP"
+	}
+	return base
+}
+
+type okxEnvelope struct {
+	Arg struct {
+		Channel string `json:"channel"`
+		InstID  string `json:"instId"`
+	} `json:"arg"`
+	Data []okxTicker `json:"data"`
+}
+
+type okxTicker struct {
+	InstID string `json:"instId"`
+	Last   string `json:"last"`
+	Vol24h string `json:"vol24h"`
+	TS     string `json:"ts"`
+}
+
+func parseOKXTicker(raw []byte) (Quote, bool) {
+	var env okxEnvelope
+	if err := json.Unmarshal(raw, &env); err != nil || len(env.Data) == 0 {
+		return Quote{}, false
+	}
+	t := env.Data[0]
+	price, err := strconv.ParseFloat(t.Last, 64)
+	if err != nil || price <= 0 {
+		return Quote{}, false
+	}
+	vol, _ := strconv.ParseFloat(t.Vol24h, 64)
+	tsMs, _ := strconv.ParseInt(t.TS, 10, 64)
+	ts := tsMs / 1000
+	if ts == 0 {
+		ts = time.Now().Unix()
+	}
+	return Quote{
+		Symbol: strings.ToLower(t.InstID),
+		Price:  price,
+		Volume: vol,
+		TS:     ts,
+		Raw:    string(raw),
+	}, true
+}
@@ -0,0 +1,124 @@
+package market
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"ai-trading-assistant/internal/store"
+)
+
+// paperBroker is the Broker value InsertOrder/RecordExecution use for
+// orders the poller emits itself, as opposed to a real broker integration.
+const paperBroker = "paper"
+
+// planLeg mirrors the subset of planagent.TradeItem's JSON shape the
+// poller needs to detect trigger/invalidate crossings. It's decoded
+// straight from PlanRecord.ContentJSON rather than imported from
+// planagent, since planagent already imports market and a reverse import
+// would cycle.
+type planLeg struct {
+	Symbol                 string  `json:"symbol"`
+	SnappedEntryPrice      float64 `json:"snapped_entry_price"`
+	SnappedQty             float64 `json:"snapped_qty"`
+	SnappedInvalidatePrice float64 `json:"snapped_invalidate_price"`
+}
+
+type planDoc struct {
+	TradePool []planLeg `json:"trade_pool"`
+}
+
+// checkPlanTriggers loads today's plan (if any) and, for every trade leg
+// matching the snapshot's symbol, checks whether the new price crossed
+// SnappedEntryPrice or SnappedInvalidatePrice relative to prev (the
+// symbol's previously cached quote). A crossing emits a paper order
+// (filled immediately at the crossing price) so the UI can show
+// plan->order->fill lineage for the day.
+func (s *Service) checkPlanTriggers(prev Quote, snapshot store.MarketSnapshot) {
+	if s.store == nil {
+		return
+	}
+	date := chinaToday()
+	plan, err := s.store.GetPlan(date)
+	if err != nil {
+		return
+	}
+
+	var doc planDoc
+	if err := json.Unmarshal([]byte(plan.ContentJSON), &doc); err != nil {
+		return
+	}
+
+	for _, leg := range doc.TradePool {
+		// A leg without a sized SnappedQty carries no position-sizing
+		// info (the plan expressed its entry purely as prose), so there's
+		// nothing to paper-trade; skip it rather than inventing a size.
+		if !strings.EqualFold(leg.Symbol, snapshot.Symbol) || leg.SnappedQty <= 0 {
+			continue
+		}
+		if leg.SnappedEntryPrice > 0 && crossed(prev.Price, snapshot.Price, leg.SnappedEntryPrice) {
+			s.emitPaperOrder(date, leg.Symbol, "buy", "entry", leg.SnappedQty, snapshot)
+		}
+		if leg.SnappedInvalidatePrice > 0 && crossed(prev.Price, snapshot.Price, leg.SnappedInvalidatePrice) {
+			s.emitPaperOrder(date, leg.Symbol, "sell", "invalidate", leg.SnappedQty, snapshot)
+		}
+	}
+}
+
+// crossed reports whether the price moved from one side of level to the
+// other between prev and next. prev == 0 means there's no prior quote to
+// compare against (the symbol's first tick), so nothing has crossed yet.
+func crossed(prev, next, level float64) bool {
+	if prev == 0 {
+		return false
+	}
+	return (prev < level) != (next < level)
+}
+
+// emitPaperOrder records a paper order for kind ("entry" or "invalidate")
+// and immediately fills it at the snapshot's price, since there's no real
+// broker behind it. ext_id is deterministic per (date, symbol, kind) so
+// InsertOrder/RecordExecution naturally dedupe repeated crossings on
+// later ticks the same day.
+func (s *Service) emitPaperOrder(planDate, symbol, side, kind string, qty float64, snapshot store.MarketSnapshot) {
+	extID := planDate + "-" + symbol + "-" + kind
+	orderID, err := s.store.InsertOrder(store.OrderRecord{
+		TS:              snapshot.TS,
+		PlanDate:        planDate,
+		TradeItemSymbol: symbol,
+		Symbol:          symbol,
+		Side:            side,
+		Qty:             qty,
+		Price:           snapshot.Price,
+		Status:          "filled",
+		Broker:          paperBroker,
+		ExtID:           extID,
+	})
+	if err != nil {
+		log.Printf("insert paper order error: %v", err)
+		return
+	}
+	if err := s.store.RecordExecution(store.ExecutionRecord{
+		OrderID: orderID,
+		TS:      snapshot.TS,
+		Symbol:  symbol,
+		Side:    side,
+		Qty:     qty,
+		Price:   snapshot.Price,
+		Broker:  paperBroker,
+		ExtID:   extID,
+	}); err != nil {
+		log.Printf("record paper execution error: %v", err)
+	}
+}
+
+// chinaToday returns today's date in Asia/Shanghai as "2006-01-02", the
+// trading-day key plans are stored under.
+func chinaToday() string {
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		return time.Now().Format("2006-01-02")
+	}
+	return time.Now().In(loc).Format("2006-01-02")
+}
@@ -0,0 +1,78 @@
+package market
+
+import "sort"
+
+// QuoteDiagnostics reports, per symbol, how many providers agreed on a
+// quorum read and which ones were flagged as price outliers.
+type QuoteDiagnostics struct {
+	Symbol   string   `json:"symbol"`
+	Median   float64  `json:"median"`
+	Agreed   int      `json:"agreed"`
+	Total    int      `json:"total"`
+	Outliers []string `json:"outliers,omitempty"`
+}
+
+// quorumResolve merges same-symbol quotes from multiple providers: it picks
+// the median price, tags quotes more than tolerancePct away from the median
+// as outliers, and reports whether at least k providers agreed (i.e. were
+// within tolerance). tieBreaker controls which quote is returned as best:
+// TieBreakerConsensus (default) picks the one closest to the median;
+// TieBreakerFreshest picks the one with the largest TS.
+func quorumResolve(symbol string, quotes []Quote, names []string, k int, tolerancePct float64, tieBreaker TieBreaker) (Quote, QuoteDiagnostics, bool) {
+	diag := QuoteDiagnostics{Symbol: symbol, Total: len(quotes)}
+	if len(quotes) == 0 {
+		return Quote{}, diag, false
+	}
+
+	prices := make([]float64, len(quotes))
+	for i, q := range quotes {
+		prices[i] = q.Price
+	}
+	sorted := append([]float64(nil), prices...)
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+	diag.Median = median
+
+	agreed := 0
+	best := quotes[0]
+	bestDist := -1.0
+	for i, q := range quotes {
+		dist := relativeDiffPct(q.Price, median)
+		if dist <= tolerancePct {
+			agreed++
+		} else if i < len(names) {
+			diag.Outliers = append(diag.Outliers, names[i])
+		}
+		switch tieBreaker {
+		case TieBreakerFreshest:
+			if q.TS > best.TS {
+				best = q
+			}
+		default:
+			if bestDist < 0 || dist < bestDist {
+				best = q
+				bestDist = dist
+			}
+		}
+	}
+	diag.Agreed = agreed
+
+	if k <= 0 {
+		k = 1
+	}
+	return best, diag, agreed >= k
+}
+
+func relativeDiffPct(price, median float64) float64 {
+	if median == 0 {
+		return 0
+	}
+	diff := (price - median) / median * 100
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff
+}
@@ -0,0 +1,74 @@
+package market
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// tokenBucket is a simple token-bucket rate limiter: it holds up to burst
+// tokens, refilled at ratePerSec, and denies a call when empty rather than
+// blocking it.
+type tokenBucket struct {
+	mu         sync.Mutex
+	ratePerSec float64
+	burst      float64
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newTokenBucket(ratePerSec float64, burst int) *tokenBucket {
+	if ratePerSec <= 0 {
+		ratePerSec = 1
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+	return &tokenBucket{
+		ratePerSec: ratePerSec,
+		burst:      float64(burst),
+		tokens:     float64(burst),
+		lastRefill: time.Now(),
+	}
+}
+
+// allow reports whether a call may proceed right now, consuming one token
+// if so.
+func (b *tokenBucket) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.lastRefill = now
+	b.tokens += elapsed * b.ratePerSec
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// RateLimitedProvider wraps a MarketProvider with a per-provider token
+// bucket so a slow/quota-limited upstream doesn't get hammered by
+// MultiProvider's parallel strategies (race/quorum/hedged).
+type RateLimitedProvider struct {
+	inner  MarketProvider
+	bucket *tokenBucket
+}
+
+// NewRateLimitedProvider wraps inner with a token bucket allowing
+// ratePerSec calls/sec, with up to burst calls in a row.
+func NewRateLimitedProvider(inner MarketProvider, ratePerSec float64, burst int) *RateLimitedProvider {
+	return &RateLimitedProvider{inner: inner, bucket: newTokenBucket(ratePerSec, burst)}
+}
+
+func (p *RateLimitedProvider) GetQuotes(ctx context.Context, symbols []string) ([]Quote, string, error) {
+	if !p.bucket.allow() {
+		return nil, "", fmt.Errorf("rate limit exceeded for %T", p.inner)
+	}
+	return p.inner.GetQuotes(ctx, symbols)
+}
@@ -0,0 +1,121 @@
+package market
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"ai-trading-assistant/internal/store"
+)
+
+// Mode selects how Service treats incoming snapshots. ModeLive quotes come
+// from provider.GetQuotes and are cached/persisted normally; ModeReplay
+// quotes are fed in by ReplayRange from stored history and are neither
+// cached nor re-persisted, so a historical run can't contaminate the live
+// quote cache or duplicate market_snapshot rows it just read.
+type Mode string
+
+const (
+	ModeLive   Mode = "live"
+	ModeReplay Mode = "replay"
+)
+
+// maxReplaySleep bounds the pause between two consecutive snapshots during
+// a paced (speed > 0) replay, so a multi-day gap in the historical data
+// doesn't stall the run for hours.
+const maxReplaySleep = 5 * time.Second
+
+// ReplayRange feeds every stored market_snapshot row for symbols between
+// from and to (inclusive, Asia/Shanghai calendar days) into engine.OnSnapshot
+// in chronological order, either paced to the snapshots' original
+// timestamps (speed > 0) or as fast as possible (speed == 0). Service is
+// switched to ModeReplay for the duration of the call, which short-circuits
+// GetQuotesWithMeta's provider calls, and its prior mode is restored once
+// the replay finishes (or ctx is cancelled).
+func (s *Service) ReplayRange(ctx context.Context, symbols []string, from, to time.Time, speed float64) error {
+	if s.store == nil {
+		return fmt.Errorf("store not configured")
+	}
+	if len(symbols) == 0 {
+		return fmt.Errorf("symbols is empty")
+	}
+
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		return fmt.Errorf("load tz: %w", err)
+	}
+	startDate := from.In(loc).Format("2006-01-02")
+	endDate := to.In(loc).Format("2006-01-02")
+
+	var feed []store.MarketSnapshot
+	for _, sym := range symbols {
+		snaps, err := s.store.QueryMarketSnapshotsRange(sym, startDate, endDate)
+		if err != nil {
+			return fmt.Errorf("query snapshots for %s: %w", sym, err)
+		}
+		feed = append(feed, snaps...)
+	}
+	sort.SliceStable(feed, func(i, j int) bool { return feed[i].TS < feed[j].TS })
+
+	s.mu.Lock()
+	prevMode := s.mode
+	s.mode = ModeReplay
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.mode = prevMode
+		s.mu.Unlock()
+	}()
+
+	var prevTS int64
+	for i, snap := range feed {
+		if speed > 0 && i > 0 {
+			if err := replayPace(ctx, prevTS, snap.TS, speed); err != nil {
+				return err
+			}
+		}
+		prevTS = snap.TS
+		s.replayIngest(snap)
+	}
+	return nil
+}
+
+func replayPace(ctx context.Context, prevTS, ts int64, speed float64) error {
+	gap := time.Duration(ts-prevTS) * time.Second
+	if gap <= 0 {
+		return nil
+	}
+	sleepFor := time.Duration(float64(gap) / speed)
+	if sleepFor > maxReplaySleep {
+		sleepFor = maxReplaySleep
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(sleepFor):
+		return nil
+	}
+}
+
+// replayIngest feeds a historical snapshot into the engine and broker, so
+// a live dashboard can watch the replay, without touching the quote cache
+// or market_snapshot table that ReplayRange just read it from.
+func (s *Service) replayIngest(snapshot store.MarketSnapshot) {
+	if snapshot.Symbol == "" {
+		return
+	}
+	if s.engine != nil {
+		s.engine.OnSnapshot(snapshot)
+	}
+	if snapshot.Price > 0 {
+		s.broker.Publish(Quote{
+			Symbol:    snapshot.Symbol,
+			Price:     snapshot.Price,
+			ChangePct: snapshot.ChangePct,
+			Volume:    snapshot.Volume,
+			TS:        snapshot.TS,
+			Raw:       snapshot.Raw,
+		})
+	}
+}
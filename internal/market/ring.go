@@ -0,0 +1,43 @@
+package market
+
+import "sync"
+
+// quoteRing keeps the most recent quote tick for a symbol so readers never
+// race the fan-out goroutine that feeds it.
+type quoteRing struct {
+	mu    sync.RWMutex
+	items []Quote
+	size  int
+	next  int
+	full  bool
+}
+
+func newQuoteRing(size int) *quoteRing {
+	if size <= 0 {
+		size = 32
+	}
+	return &quoteRing{items: make([]Quote, size), size: size}
+}
+
+func (r *quoteRing) push(q Quote) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items[r.next] = q
+	r.next = (r.next + 1) % r.size
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+func (r *quoteRing) latest() (Quote, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if !r.full && r.next == 0 {
+		return Quote{}, false
+	}
+	idx := r.next - 1
+	if idx < 0 {
+		idx = r.size - 1
+	}
+	return r.items[idx], true
+}
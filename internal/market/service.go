@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"ai-trading-assistant/internal/engine"
+	"ai-trading-assistant/internal/metrics"
 	"ai-trading-assistant/internal/store"
 )
 
@@ -17,8 +18,10 @@ type Service struct {
 	minInterval time.Duration
 	store       *store.Store
 	engine      *engine.Engine
+	broker      *Broker
 
 	mu                  sync.Mutex
+	mode                Mode
 	lastFetch           time.Time
 	cache               map[string]Quote
 	consecutiveFailures int
@@ -33,10 +36,43 @@ func NewService(provider MarketProvider, minInterval time.Duration, st *store.St
 		minInterval: minInterval,
 		store:       st,
 		engine:      eng,
+		broker:      NewBroker(),
 		cache:       make(map[string]Quote),
+		mode:        ModeLive,
 	}
 }
 
+// Mode reports whether the Service is currently serving live quotes or
+// replaying history via ReplayRange.
+func (s *Service) Mode() Mode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mode
+}
+
+// Broker returns the pub/sub layer that every ingested quote is fanned out
+// to, so API-layer streaming handlers (e.g. the WebSocket quotes feed) can
+// subscribe without the market package knowing about transports.
+func (s *Service) Broker() *Broker {
+	return s.broker
+}
+
+// StatusProvider is implemented by MarketProviders that can report
+// per-provider health (currently only MultiProvider).
+type StatusProvider interface {
+	Status() []ProviderStatus
+}
+
+// ProviderStatus reports the configured providers' health, or (nil, false)
+// if the underlying provider doesn't track per-provider status.
+func (s *Service) ProviderStatus() ([]ProviderStatus, bool) {
+	sp, ok := s.provider.(StatusProvider)
+	if !ok {
+		return nil, false
+	}
+	return sp.Status(), true
+}
+
 func (s *Service) GetQuotes(symbols []string) ([]Quote, error) {
 	quotes, _, _, _, _, err := s.GetQuotesWithMeta(symbols)
 	return quotes, err
@@ -49,6 +85,9 @@ func (s *Service) GetQuotesWithMeta(symbols []string) ([]Quote, bool, string, in
 	if len(symbols) == 0 {
 		return nil, false, "", 0, nil, fmt.Errorf("symbols is empty")
 	}
+	if s.Mode() == ModeReplay {
+		return nil, false, "", 0, nil, fmt.Errorf("market service is in replay mode: live quotes unavailable")
+	}
 
 	now := time.Now()
 	s.mu.Lock()
@@ -59,12 +98,15 @@ func (s *Service) GetQuotesWithMeta(symbols []string) ([]Quote, bool, string, in
 		if err != nil {
 			return nil, false, "", 0, nil, err
 		}
+		metrics.MarketQuoteStaleTotal.Inc()
 		return cached, true, "cache", sourceTS, []string{"请求过快，返回缓存数据"}, nil
 	}
 	s.mu.Unlock()
 
+	fetchStart := time.Now()
 	quotes, source, err := s.provider.GetQuotes(context.Background(), symbols)
 	if err == nil {
+		metrics.MarketQuoteFetchDuration.WithLabelValues("live").Observe(time.Since(fetchStart).Seconds())
 		s.mu.Lock()
 		for _, q := range quotes {
 			s.cache[strings.ToLower(q.Symbol)] = q
@@ -74,6 +116,7 @@ func (s *Service) GetQuotesWithMeta(symbols []string) ([]Quote, bool, string, in
 		s.mu.Unlock()
 		return quotes, false, source, time.Now().Unix(), nil, nil
 	}
+	metrics.MarketQuoteFetchDuration.WithLabelValues("error").Observe(time.Since(fetchStart).Seconds())
 
 	s.mu.Lock()
 	s.consecutiveFailures++
@@ -81,6 +124,7 @@ func (s *Service) GetQuotesWithMeta(symbols []string) ([]Quote, bool, string, in
 	sourceTS := maxQuoteTSLocked(cached)
 	s.mu.Unlock()
 	if cacheErr == nil {
+		metrics.MarketQuoteStaleTotal.Inc()
 		return cached, true, "cache", sourceTS, []string{fmt.Sprintf("行情获取失败，已返回缓存：%v", err)}, nil
 	}
 
@@ -108,15 +152,36 @@ func (s *Service) PollAndStore(symbols []string) error {
 	return nil
 }
 
-func (s *Service) PollLoop(symbols []string, baseInterval time.Duration) {
+// PollLoop polls symbols on baseInterval (backing off on repeated failures)
+// until ctx is cancelled, so a config.Manager reload that changes
+// Market.Symbols or Market.PollIntervalSec can cancel and restart it with a
+// fresh interval instead of leaving a stale loop running.
+func (s *Service) PollLoop(ctx context.Context, symbols []string, baseInterval time.Duration) {
 	if baseInterval <= 0 {
 		baseInterval = 3 * time.Second
 	}
 	for {
 		err := s.PollAndStore(symbols)
 		interval := s.nextPollInterval(baseInterval, err != nil)
-		time.Sleep(interval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// SetMinInterval adjusts the cache-serving window GetQuotesWithMeta uses to
+// decide between a fresh fetch and a cached response, so a config.Manager
+// reload can tighten or loosen rate limiting without reconstructing the
+// provider chain.
+func (s *Service) SetMinInterval(d time.Duration) {
+	if d < 0 {
+		d = 0
 	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.minInterval = d
 }
 
 func (s *Service) nextPollInterval(base time.Duration, failed bool) time.Duration {
@@ -152,6 +217,22 @@ func (s *Service) IngestSnapshot(snapshot store.MarketSnapshot) {
 	s.ingestSnapshot(snapshot)
 }
 
+// IngestQuote implements QuoteSink, letting a MultiStreamProvider drive the
+// store/engine/broker pipeline directly from streamed ticks — the same
+// ingestion path PollAndStore uses for polled ones — so the engine can be
+// driven by either mode interchangeably.
+func (s *Service) IngestQuote(q Quote) {
+	s.ingestSnapshot(store.MarketSnapshot{
+		TS:        q.TS,
+		Symbol:    q.Symbol,
+		Name:      q.Name,
+		Price:     q.Price,
+		ChangePct: q.ChangePct,
+		Volume:    q.Volume,
+		Raw:       q.Raw,
+	})
+}
+
 func (s *Service) ingestSnapshot(snapshot store.MarketSnapshot) {
 	if snapshot.Symbol == "" {
 		return
@@ -169,8 +250,7 @@ func (s *Service) ingestSnapshot(snapshot store.MarketSnapshot) {
 	}
 
 	if snapshot.Price > 0 {
-		s.mu.Lock()
-		s.cache[strings.ToLower(snapshot.Symbol)] = Quote{
+		quote := Quote{
 			Symbol:    snapshot.Symbol,
 			Price:     snapshot.Price,
 			ChangePct: snapshot.ChangePct,
@@ -178,7 +258,12 @@ func (s *Service) ingestSnapshot(snapshot store.MarketSnapshot) {
 			TS:        snapshot.TS,
 			Raw:       snapshot.Raw,
 		}
+		s.mu.Lock()
+		prev := s.cache[strings.ToLower(snapshot.Symbol)]
+		s.cache[strings.ToLower(snapshot.Symbol)] = quote
 		s.mu.Unlock()
+		s.broker.Publish(quote)
+		s.checkPlanTriggers(prev, snapshot)
 	}
 }
 
@@ -0,0 +1,12 @@
+package market
+
+import "context"
+
+// StreamingProvider exposes a push-based quote feed as an alternative to the
+// batched MarketProvider RPC. Implementations close the returned channel
+// once ctx is done or the underlying connection drops; they do not
+// reconnect themselves, MultiStreamProvider resubscribes with backoff.
+type StreamingProvider interface {
+	Name() string
+	Subscribe(ctx context.Context, symbols []string) (<-chan Quote, error)
+}
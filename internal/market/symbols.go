@@ -0,0 +1,100 @@
+package market
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Exchange identifies which market a normalized symbol belongs to.
+type Exchange string
+
+const (
+	ExchangeSH Exchange = "sh" // Shanghai
+	ExchangeSZ Exchange = "sz" // Shenzhen
+	ExchangeBJ Exchange = "bj" // Beijing
+	ExchangeHK Exchange = "hk" // Hong Kong
+	ExchangeUS Exchange = "us" // US ADR
+)
+
+// ParseSymbol splits a normalized symbol (e.g. "sh600000", "bj430047",
+// "hk00700", "us.aapl") into its exchange and bare code, so every provider
+// can build its own wire format from one shared mapping instead of each
+// re-implementing prefix detection.
+func ParseSymbol(symbol string) (Exchange, string, error) {
+	s := strings.ToLower(strings.TrimSpace(symbol))
+	s = strings.TrimPrefix(s, "us.") // allow the "us.<ticker>" form used for ADRs
+	for _, ex := range []Exchange{ExchangeSH, ExchangeSZ, ExchangeBJ, ExchangeHK} {
+		if strings.HasPrefix(s, string(ex)) {
+			code := strings.TrimPrefix(s, string(ex))
+			if code == "" {
+				return "", "", fmt.Errorf("invalid symbol: %s", symbol)
+			}
+			return ex, code, nil
+		}
+	}
+	// No A-share/HK prefix: treat as a bare US ticker (e.g. "aapl").
+	if s == "" {
+		return "", "", fmt.Errorf("invalid symbol: %s", symbol)
+	}
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			return "", "", fmt.Errorf("invalid symbol: %s", symbol)
+		}
+	}
+	return ExchangeUS, s, nil
+}
+
+// ToSecID maps a normalized symbol to Eastmoney's secid wire format
+// ("<market id>.<code>").
+func ToSecID(symbol string) (string, error) {
+	ex, code, err := ParseSymbol(symbol)
+	if err != nil {
+		return "", err
+	}
+	switch ex {
+	case ExchangeSH:
+		return "1." + code, nil
+	case ExchangeSZ, ExchangeBJ:
+		return "0." + code, nil
+	case ExchangeHK:
+		return "116." + code, nil
+	case ExchangeUS:
+		return "105." + strings.ToUpper(code), nil
+	}
+	return "", fmt.Errorf("invalid symbol: %s", symbol)
+}
+
+// ToTencentCode maps a normalized symbol to Tencent/gtimg's wire format
+// (exchange prefix directly followed by the code, e.g. "sh600000",
+// "hk00700", "usAAPL").
+func ToTencentCode(symbol string) (string, error) {
+	ex, code, err := ParseSymbol(symbol)
+	if err != nil {
+		return "", err
+	}
+	if ex == ExchangeUS {
+		return "us" + strings.ToUpper(code), nil
+	}
+	return string(ex) + code, nil
+}
+
+// ToTushareCode maps a normalized symbol to Tushare Pro's ts_code wire
+// format ("<code>.<EXCHANGE>", e.g. "600000.SH", "000001.SZ", "00700.HK").
+// Tushare Pro has no US ADR coverage, so ExchangeUS symbols are rejected.
+func ToTushareCode(symbol string) (string, error) {
+	ex, code, err := ParseSymbol(symbol)
+	if err != nil {
+		return "", err
+	}
+	switch ex {
+	case ExchangeSH:
+		return strings.ToUpper(code) + ".SH", nil
+	case ExchangeSZ:
+		return strings.ToUpper(code) + ".SZ", nil
+	case ExchangeBJ:
+		return strings.ToUpper(code) + ".BJ", nil
+	case ExchangeHK:
+		return strings.ToUpper(code) + ".HK", nil
+	}
+	return "", fmt.Errorf("tushare: unsupported exchange for symbol: %s", symbol)
+}
@@ -0,0 +1,107 @@
+package market
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TencentProvider reads quotes from Tencent/gtimg's public quote feed, the
+// same one Sina-style frontends use as a secondary source.
+type TencentProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewTencentProvider(timeout time.Duration) *TencentProvider {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &TencentProvider{
+		baseURL: "https://qt.gtimg.cn/q=",
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *TencentProvider) GetQuotes(ctx context.Context, symbols []string) ([]Quote, string, error) {
+	if len(symbols) == 0 {
+		return nil, "", fmt.Errorf("symbols is empty")
+	}
+	codes := make([]string, 0, len(symbols))
+	for _, sym := range symbols {
+		code, err := ToTencentCode(sym)
+		if err != nil {
+			return nil, "", err
+		}
+		codes = append(codes, code)
+	}
+
+	url := p.baseURL + strings.Join(codes, ",")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("build request: %w", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("request tencent: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := readAll(resp)
+	if err != nil {
+		return nil, "", fmt.Errorf("read tencent: %w", err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	out := make([]Quote, 0, len(symbols))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		q, ok := parseTencentLine(line)
+		if ok {
+			out = append(out, q)
+		}
+	}
+	if len(out) == 0 {
+		return nil, "", fmt.Errorf("empty tencent response")
+	}
+	return out, "tencent", nil
+}
+
+func parseTencentLine(line string) (Quote, bool) {
+	// format: v_sh600000="1~浦发银行~600000~10.50~10.40~...~volume~...";
+	parts := strings.Split(line, "=")
+	if len(parts) < 2 {
+		return Quote{}, false
+	}
+	sym := strings.TrimPrefix(strings.TrimSpace(parts[0]), "v_")
+	payload := strings.Trim(parts[1], ";")
+	payload = strings.Trim(payload, "\"")
+	fields := strings.Split(payload, "~")
+	if len(fields) < 10 {
+		return Quote{}, false
+	}
+	name := fields[1]
+	price := parseFloat(fields[3])
+	preclose := parseFloat(fields[4])
+	volume := parseFloat(fields[6])
+	if price <= 0 {
+		return Quote{}, false
+	}
+	changePct := 0.0
+	if preclose > 0 {
+		changePct = (price - preclose) / preclose * 100
+	}
+	return Quote{
+		Symbol:    strings.ToLower(sym),
+		Name:      name,
+		Price:     price,
+		ChangePct: changePct,
+		Volume:    volume,
+		TS:        time.Now().Unix(),
+		Raw:       payload,
+	}, true
+}
@@ -0,0 +1,156 @@
+package market
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TushareProvider reads quotes from the Tushare Pro JSON API
+// (https://api.tushare.pro). Unlike Sina/Eastmoney/Tencent, Tushare Pro is
+// not an anonymous public feed: every request carries a per-account Token,
+// so a provider with an empty Token is treated as unusable rather than
+// attempted.
+type TushareProvider struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+func NewTushareProvider(token string, timeout time.Duration) *TushareProvider {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &TushareProvider{
+		baseURL: "https://api.tushare.pro",
+		token:   token,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+type tushareRequest struct {
+	APIName string         `json:"api_name"`
+	Token   string         `json:"token"`
+	Params  map[string]any `json:"params"`
+	Fields  string         `json:"fields"`
+}
+
+type tushareResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+	Data struct {
+		Fields []string `json:"fields"`
+		Items  [][]any  `json:"items"`
+	} `json:"data"`
+}
+
+func (p *TushareProvider) GetQuotes(ctx context.Context, symbols []string) ([]Quote, string, error) {
+	if p.token == "" {
+		return nil, "", fmt.Errorf("tushare: token not configured")
+	}
+	if len(symbols) == 0 {
+		return nil, "", fmt.Errorf("symbols is empty")
+	}
+
+	codes := make([]string, 0, len(symbols))
+	symbolByCode := make(map[string]string, len(symbols))
+	for _, sym := range symbols {
+		code, err := ToTushareCode(sym)
+		if err != nil {
+			return nil, "", err
+		}
+		codes = append(codes, code)
+		symbolByCode[code] = strings.ToLower(sym)
+	}
+
+	body, err := json.Marshal(tushareRequest{
+		APIName: "realtime_quote",
+		Token:   p.token,
+		Params:  map[string]any{"ts_code": strings.Join(codes, ",")},
+		Fields:  "ts_code,name,price,pre_close,vol",
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("encode tushare request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, "", fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("request tushare: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out tushareResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, "", fmt.Errorf("decode tushare: %w", err)
+	}
+	if out.Code != 0 {
+		return nil, "", fmt.Errorf("tushare: %s", out.Msg)
+	}
+
+	col := make(map[string]int, len(out.Data.Fields))
+	for i, f := range out.Data.Fields {
+		col[f] = i
+	}
+
+	quotes := make([]Quote, 0, len(out.Data.Items))
+	for _, row := range out.Data.Items {
+		tsCode, _ := cellString(row, col["ts_code"])
+		symbol, ok := symbolByCode[tsCode]
+		if !ok {
+			continue
+		}
+		price := cellFloat(row, col["price"])
+		if price <= 0 {
+			continue
+		}
+		preClose := cellFloat(row, col["pre_close"])
+		changePct := 0.0
+		if preClose > 0 {
+			changePct = (price - preClose) / preClose * 100
+		}
+		name, _ := cellString(row, col["name"])
+		quotes = append(quotes, Quote{
+			Symbol:    symbol,
+			Name:      name,
+			Price:     price,
+			ChangePct: changePct,
+			Volume:    cellFloat(row, col["vol"]),
+			TS:        time.Now().Unix(),
+		})
+	}
+	if len(quotes) == 0 {
+		return nil, "", fmt.Errorf("empty tushare response")
+	}
+	return quotes, "tushare", nil
+}
+
+func cellString(row []any, i int) (string, bool) {
+	if i < 0 || i >= len(row) {
+		return "", false
+	}
+	s, ok := row[i].(string)
+	return s, ok
+}
+
+func cellFloat(row []any, i int) float64 {
+	if i < 0 || i >= len(row) {
+		return 0
+	}
+	switch t := row[i].(type) {
+	case float64:
+		return t
+	case string:
+		return parseFloat(t)
+	}
+	return 0
+}
@@ -3,15 +3,41 @@ package market
 import "context"
 
 type Quote struct {
-	Symbol    string  `json:"symbol"`
-	Name      string  `json:"name,omitempty"`
-	Price     float64 `json:"price"`
-	ChangePct float64 `json:"change_pct,omitempty"`
-	Volume    float64 `json:"volume,omitempty"`
-	TS        int64   `json:"ts"`
-	Raw       string  `json:"raw,omitempty"`
+	Symbol    string        `json:"symbol"`
+	Name      string        `json:"name,omitempty"`
+	Price     float64       `json:"price"`
+	ChangePct float64       `json:"change_pct,omitempty"`
+	Volume    float64       `json:"volume,omitempty"`
+	TS        int64         `json:"ts"`
+	Raw       string        `json:"raw,omitempty"`
+	Contract  *ContractInfo `json:"contract,omitempty"`
+	// Source names the provider that produced this quote. Set by
+	// MultiProvider so multi-source callers can see provider selection
+	// per symbol, not just per batch.
+	Source string `json:"source,omitempty"`
+}
+
+// ContractInfo carries the derivatives-specific fields a spot Quote doesn't
+// have. It's populated by FuturesProvider implementations and left nil for
+// plain equity/index quotes.
+type ContractInfo struct {
+	MarkPrice          float64 `json:"mark_price,omitempty"`
+	IndexPrice         float64 `json:"index_price,omitempty"`
+	FundingRate        float64 `json:"funding_rate,omitempty"`
+	NextFundingTS      int64   `json:"next_funding_ts,omitempty"`
+	OpenInterest       float64 `json:"open_interest,omitempty"`
+	ContractMultiplier float64 `json:"contract_multiplier,omitempty"`
+	TickSize           float64 `json:"tick_size,omitempty"`
+	PositionSide       string  `json:"position_side,omitempty"` // long/short/both
 }
 
 type MarketProvider interface {
 	GetQuotes(ctx context.Context, symbols []string) ([]Quote, string, error)
 }
+
+// FuturesProvider is implemented by providers that can report derivatives
+// contract state (mark/index price, funding, open interest) alongside the
+// quote itself.
+type FuturesProvider interface {
+	GetContractQuotes(ctx context.Context, symbols []string) ([]Quote, string, error)
+}
@@ -0,0 +1,41 @@
+package market
+
+import (
+	"context"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsHeartbeatInterval = 30 * time.Second
+	wsPongWait          = 60 * time.Second
+)
+
+// startWSHeartbeat sends periodic ping control frames on conn and extends
+// its read deadline whenever a pong arrives, so a half-open connection (the
+// TCP session stays up but the exchange has stopped publishing) is detected
+// and the read loop unblocks instead of hanging forever. Shared by
+// BinanceWSProvider and OKXWSProvider since neither gateway reliably closes
+// a dead connection on its own.
+func startWSHeartbeat(ctx context.Context, conn *websocket.Conn) {
+	_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	ticker := time.NewTicker(wsHeartbeatInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second)); err != nil {
+					return
+				}
+			}
+		}
+	}()
+}
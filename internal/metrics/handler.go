@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+)
+
+// Handler serves the default Prometheus registry in text exposition format
+// at /metrics, so the module can be scraped by Prometheus directly.
+func Handler() app.HandlerFunc {
+	format := expfmt.NewFormat(expfmt.TypeTextPlain)
+	return func(_ context.Context, c *app.RequestContext) {
+		mfs, err := prometheus.DefaultGatherer.Gather()
+		if err != nil {
+			c.String(consts.StatusInternalServerError, "gather metrics: %v", err)
+			return
+		}
+
+		var buf bytes.Buffer
+		enc := expfmt.NewEncoder(&buf, format)
+		for _, mf := range mfs {
+			if err := enc.Encode(mf); err != nil {
+				c.String(consts.StatusInternalServerError, "encode metrics: %v", err)
+				return
+			}
+		}
+		c.Data(consts.StatusOK, string(format), buf.Bytes())
+	}
+}
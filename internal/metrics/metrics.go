@@ -0,0 +1,109 @@
+// Package metrics holds the process-wide Prometheus collectors used across
+// the API, alerting, market, and agent subsystems. Collectors are
+// package-level singletons registered with the default registry via
+// promauto, the usual way client_golang is wired up; callers just reach for
+// the variable they need and call Observe/Inc.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total HTTP requests handled, by method, route, and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds, by method and route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// AlertOutcomesTotal buckets every alert.Service.Handle outcome by its
+	// Status, plus a synthetic "error" bucket for results that carried a
+	// non-nil Result.Error (there's no alert.StatusError constant, so the
+	// error case is reported separately from the status it occurred under).
+	AlertOutcomesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "alert_outcomes_total",
+		Help: "Alert delivery outcomes, by status (sent/suppressed/queued_digest/merged_pending/error).",
+	}, []string{"status"})
+
+	DingTalkSendDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "dingtalk_send_duration_seconds",
+		Help:    "DingTalk webhook send latency in seconds.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	DingTalkErrCodeTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dingtalk_errcode_total",
+		Help: "DingTalk webhook responses, by errcode (0 = success; transport_error/decode_error when no response was decoded).",
+	}, []string{"errcode"})
+
+	MarketQuoteFetchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "market_quote_fetch_duration_seconds",
+		Help:    "Market quote fetch latency in seconds, by result (live/error).",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"result"})
+
+	MarketQuoteStaleTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "market_quote_stale_total",
+		Help: "Count of quote responses served from stale cache.",
+	})
+
+	PlanGenerationTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "plan_generation_total",
+		Help: "Plan generations, by mode (llm/fallback).",
+	}, []string{"mode"})
+
+	RiskAgentEvalDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "risk_agent_eval_duration_seconds",
+		Help:    "Risk agent evaluation latency in seconds, by mode (llm/fallback).",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"mode"})
+
+	EngineRuleFiresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "engine_rule_fires_total",
+		Help: "Engine rule evaluations that emitted an event, by rule, severity, and symbol.",
+	}, []string{"rule", "severity", "symbol"})
+
+	EngineCooldownSuppressedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "engine_cooldown_suppressed_total",
+		Help: "Rule fires suppressed by an active cooldown, by rule and symbol.",
+	}, []string{"rule", "symbol"})
+
+	EngineSnapshotLagSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "engine_snapshot_lag_seconds",
+		Help:    "Lag between a market snapshot's timestamp and when the engine observed it.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	EngineWindowsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "engine_windows_symbols",
+		Help: "Number of symbols with a live rolling window in the engine.",
+	})
+
+	EngineCooldownGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "engine_cooldown_keys",
+		Help: "Number of (rule, symbol, severity) keys with an active cooldown timestamp.",
+	})
+
+	NotifierSendDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "notifier_send_duration_seconds",
+		Help:    "Notifier channel send latency in seconds, by channel.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"channel"})
+
+	MarketProviderRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "market_provider_request_duration_seconds",
+		Help:    "Per-provider market quote request latency in seconds, by provider.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"provider"})
+
+	MarketProviderErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "market_provider_errors_total",
+		Help: "Per-provider market quote request failures, by provider.",
+	}, []string{"provider"})
+)
@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// Middleware records HTTPRequestsTotal/HTTPRequestDuration for every request
+// routed through Hertz. Register it with h.Use before RegisterRoutes so it
+// wraps every handler, including ones added later.
+func Middleware() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		start := time.Now()
+		c.Next(ctx)
+
+		route := string(c.FullPath())
+		if route == "" {
+			route = "unmatched"
+		}
+		method := string(c.Method())
+		status := strconv.Itoa(c.Response.StatusCode())
+
+		HTTPRequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+		HTTPRequestsTotal.WithLabelValues(method, route, status).Inc()
+	}
+}
@@ -0,0 +1,34 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	"ai-trading-assistant/internal/push/dingtalk"
+)
+
+// DingtalkNotifier adapts the existing push/dingtalk.Client to the Notifier
+// interface so it can be registered alongside Lark and webhook channels.
+type DingtalkNotifier struct {
+	client *dingtalk.Client
+}
+
+func NewDingtalkNotifier(client *dingtalk.Client) *DingtalkNotifier {
+	return &DingtalkNotifier{client: client}
+}
+
+func (n *DingtalkNotifier) Name() string { return "dingtalk" }
+
+func (n *DingtalkNotifier) Send(ctx context.Context, title, markdown string, severity string) error {
+	if n.client == nil {
+		return fmt.Errorf("dingtalk client not configured")
+	}
+	resp, err := n.client.SendMarkdown(ctx, title, markdown)
+	if err != nil {
+		return err
+	}
+	if resp.ErrCode != 0 {
+		return fmt.Errorf("dingtalk errcode=%d errmsg=%s", resp.ErrCode, resp.ErrMsg)
+	}
+	return nil
+}
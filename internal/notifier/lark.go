@@ -0,0 +1,54 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ai-trading-assistant/internal/push/lark"
+)
+
+// LarkClient adapts the shared push/lark.Client to the Notifier interface,
+// mapping severity to card header color and attaching the standard
+// acknowledge/snooze/mute action row. Severity maps to card header color:
+// low=green, med=orange, high=red.
+type LarkClient struct {
+	client *lark.Client
+}
+
+func NewLarkClient(webhook, secret string, timeout time.Duration) *LarkClient {
+	return &LarkClient{client: lark.NewClient(webhook, secret, timeout)}
+}
+
+func (c *LarkClient) Name() string { return "lark" }
+
+func (c *LarkClient) Send(ctx context.Context, title, markdown string, severity string) error {
+	resp, err := c.client.SendCard(ctx, lark.Card{
+		Title:    title,
+		Markdown: markdown,
+		Template: cardColor(severity),
+		Actions: []lark.Action{
+			{Text: "已确认", Value: "confirm", Type: "default"},
+			{Text: "静默15分钟", Value: "snooze_15m", Type: "default"},
+			{Text: "屏蔽该标的", Value: "mute_symbol", Type: "danger"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Code != 0 {
+		return fmt.Errorf("lark errcode=%d errmsg=%s", resp.Code, resp.Msg)
+	}
+	return nil
+}
+
+func cardColor(severity string) string {
+	switch severity {
+	case "high":
+		return "red"
+	case "med":
+		return "orange"
+	default:
+		return "green"
+	}
+}
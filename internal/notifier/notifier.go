@@ -0,0 +1,11 @@
+package notifier
+
+import "context"
+
+// Notifier delivers a rendered markdown message to an external channel.
+// Implementations translate title/markdown into their own payload schema
+// (DingTalk markdown, Lark interactive card, a generic webhook body, ...).
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, title, markdown string, severity string) error
+}
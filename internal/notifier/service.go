@@ -0,0 +1,151 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"ai-trading-assistant/internal/metrics"
+	"ai-trading-assistant/internal/store"
+)
+
+// Config tunes the per-(symbol,event-type) rate limiting and dedup window
+// shared by every registered Notifier, plus the per-channel retry policy
+// used when a Send call fails.
+type Config struct {
+	DedupWindow     time.Duration
+	MinSendInterval time.Duration
+
+	// MaxRetries is how many additional Send attempts a channel gets after
+	// its first failure. RetryBackoff is multiplied by the attempt number
+	// between retries (1x, 2x, ...).
+	MaxRetries   int
+	RetryBackoff time.Duration
+}
+
+// Service fans a single decision out to every registered Notifier,
+// deduplicating identical (symbol, event_type) pushes within DedupWindow and
+// persisting a delivery log so failed sends can be audited.
+type Service struct {
+	notifiers []Notifier
+	cfg       Config
+	store     *store.Store
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+}
+
+func NewService(cfg Config, st *store.Store, notifiers ...Notifier) *Service {
+	if cfg.DedupWindow <= 0 {
+		cfg.DedupWindow = 60 * time.Second
+	}
+	if cfg.MinSendInterval <= 0 {
+		cfg.MinSendInterval = 5 * time.Second
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 2
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = 500 * time.Millisecond
+	}
+	return &Service{
+		notifiers: notifiers,
+		cfg:       cfg,
+		store:     st,
+		lastSent:  make(map[string]time.Time),
+	}
+}
+
+// Notify renders title/markdown to every registered channel in parallel,
+// unless the (symbol, eventType) key was already sent within the dedup
+// window. Each channel gets its own retry/backoff and a slow or failing
+// channel never delays the others.
+func (s *Service) Notify(ctx context.Context, symbol, eventType, title, markdown, severity string) {
+	if s == nil || len(s.notifiers) == 0 {
+		return
+	}
+	key := symbol + ":" + eventType
+	if s.isRateLimited(key) {
+		return
+	}
+	var wg sync.WaitGroup
+	for _, n := range s.notifiers {
+		wg.Add(1)
+		go func(n Notifier) {
+			defer wg.Done()
+			err := s.sendWithRetry(ctx, n, title, markdown, severity)
+			s.recordDelivery(n.Name(), key, title, markdown, err)
+			if err != nil {
+				log.Printf("notifier %s send error: %v", n.Name(), err)
+			}
+		}(n)
+	}
+	wg.Wait()
+}
+
+// sendWithRetry retries n.Send up to s.cfg.MaxRetries additional times,
+// waiting RetryBackoff*attempt between tries, and returns the last error if
+// every attempt fails.
+func (s *Service) sendWithRetry(ctx context.Context, n Notifier, title, markdown, severity string) error {
+	start := time.Now()
+	defer func() {
+		metrics.NotifierSendDuration.WithLabelValues(n.Name()).Observe(time.Since(start).Seconds())
+	}()
+
+	var lastErr error
+	for attempt := 0; attempt <= s.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(s.cfg.RetryBackoff * time.Duration(attempt))
+		}
+		if err := n.Send(ctx, title, markdown, severity); err != nil {
+			lastErr = err
+			continue
+		}
+		return nil
+	}
+	return lastErr
+}
+
+func (s *Service) isRateLimited(key string) bool {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if last, ok := s.lastSent[key]; ok {
+		window := s.cfg.DedupWindow
+		if window < s.cfg.MinSendInterval {
+			window = s.cfg.MinSendInterval
+		}
+		if now.Sub(last) < window {
+			return true
+		}
+	}
+	s.lastSent[key] = now
+	return false
+}
+
+func (s *Service) recordDelivery(channel, dedupKey, title, markdown string, sendErr error) {
+	if s.store == nil {
+		return
+	}
+	status := "sent"
+	errMsg := ""
+	if sendErr != nil {
+		status = "error"
+		errMsg = sendErr.Error()
+	}
+	payload, _ := json.Marshal(map[string]string{"title": title, "markdown": markdown})
+	rec := store.NotifierDeliveryRecord{
+		TS:       time.Now().Unix(),
+		Channel:  channel,
+		DedupKey: dedupKey,
+		Title:    title,
+		Payload:  string(payload),
+		Status:   status,
+		Error:    errMsg,
+	}
+	if err := s.store.InsertNotifierDelivery(rec); err != nil {
+		log.Printf("insert notifier delivery error: %v", err)
+	}
+}
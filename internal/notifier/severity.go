@@ -0,0 +1,28 @@
+package notifier
+
+import "context"
+
+var severityRank = map[string]int{"low": 0, "med": 1, "high": 2}
+
+// SeverityFilter wraps a Notifier so Send is a no-op below minSeverity —
+// e.g. "only high goes to Telegram" — the same decorator shape
+// market.NewRateLimitedProvider uses to wrap a MarketProvider.
+type SeverityFilter struct {
+	inner       Notifier
+	minSeverity string
+}
+
+// NewSeverityFilter wraps inner, dropping Send calls below minSeverity. An
+// empty/unrecognized minSeverity is treated as "low" (no filtering).
+func NewSeverityFilter(inner Notifier, minSeverity string) *SeverityFilter {
+	return &SeverityFilter{inner: inner, minSeverity: minSeverity}
+}
+
+func (f *SeverityFilter) Name() string { return f.inner.Name() }
+
+func (f *SeverityFilter) Send(ctx context.Context, title, markdown string, severity string) error {
+	if severityRank[severity] < severityRank[f.minSeverity] {
+		return nil
+	}
+	return f.inner.Send(ctx, title, markdown, severity)
+}
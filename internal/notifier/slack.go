@@ -0,0 +1,32 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ai-trading-assistant/internal/push/slack"
+)
+
+// SlackNotifier adapts the shared push/slack.Client to the Notifier
+// interface.
+type SlackNotifier struct {
+	client *slack.Client
+}
+
+func NewSlackNotifier(webhookURL string, timeout time.Duration) *SlackNotifier {
+	return &SlackNotifier{client: slack.NewClient(webhookURL, timeout)}
+}
+
+func (n *SlackNotifier) Name() string { return "slack" }
+
+func (n *SlackNotifier) Send(ctx context.Context, title, markdown string, severity string) error {
+	status, err := n.client.Send(ctx, title, markdown)
+	if err != nil {
+		return err
+	}
+	if status >= 300 {
+		return fmt.Errorf("slack webhook returned status %d", status)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// TelegramNotifier posts to a Telegram bot's sendMessage API.
+type TelegramNotifier struct {
+	botToken   string
+	chatID     string
+	httpClient *http.Client
+}
+
+func NewTelegramNotifier(botToken, chatID string, timeout time.Duration) *TelegramNotifier {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &TelegramNotifier{
+		botToken:   botToken,
+		chatID:     chatID,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (n *TelegramNotifier) Name() string { return "telegram" }
+
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+func (n *TelegramNotifier) Send(ctx context.Context, title, markdown string, severity string) error {
+	if n.botToken == "" || n.chatID == "" {
+		return fmt.Errorf("telegram bot_token/chat_id not configured")
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"chat_id":    n.chatID,
+		"text":       fmt.Sprintf("*%s*\n%s", title, markdown),
+		"parse_mode": "Markdown",
+	})
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", n.botToken)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out telegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if !out.OK {
+		return fmt.Errorf("telegram error: %s", out.Description)
+	}
+	return nil
+}
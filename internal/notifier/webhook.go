@@ -0,0 +1,42 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"ai-trading-assistant/internal/push/webhook"
+)
+
+// WebhookNotifier adapts the shared push/webhook.Client to the Notifier
+// interface. It's the escape hatch for channels without a bespoke
+// implementation.
+type WebhookNotifier struct {
+	client *webhook.Client
+}
+
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{client: webhook.NewClient(url, "", timeout)}
+}
+
+func (n *WebhookNotifier) Name() string { return "webhook" }
+
+func (n *WebhookNotifier) Send(ctx context.Context, title, markdown string, severity string) error {
+	body, err := json.Marshal(map[string]string{
+		"title":    title,
+		"markdown": markdown,
+		"severity": severity,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+	status, err := n.client.Post(ctx, body)
+	if err != nil {
+		return err
+	}
+	if status >= 300 {
+		return fmt.Errorf("webhook returned status %d", status)
+	}
+	return nil
+}
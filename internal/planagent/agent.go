@@ -7,11 +7,16 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/cloudwego/eino-ext/components/model/openai"
 	"github.com/cloudwego/eino/schema"
+	"github.com/eino-contrib/jsonschema"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+
+	"ai-trading-assistant/internal/market"
 )
 
 type Config struct {
@@ -22,6 +27,11 @@ type Config struct {
 	ByAzure    bool   `yaml:"by_azure"`
 	APIVersion string `yaml:"api_version"`
 	TimeoutMs  int    `yaml:"timeout_ms"`
+	// MaxRepairAttempts bounds how many times Evaluate re-issues the
+	// request (with the schema and the previous invalid output attached)
+	// after a parse/validation failure, before giving up and returning
+	// FallbackPlan. Defaults to 2 when zero.
+	MaxRepairAttempts int `yaml:"max_repair_attempts"`
 }
 
 type Plan struct {
@@ -38,6 +48,24 @@ type TradeItem struct {
 	Invalidate  string  `json:"invalidate"`
 	PositionPct float64 `json:"position_pct"`
 	StopLoss    string  `json:"stop_loss"`
+	// EntryPrice and Qty are the raw, unrounded values the model (or the
+	// fallback planner) proposed. They're optional: a plan can express an
+	// entry purely through Trigger's free text instead.
+	EntryPrice float64 `json:"entry_price,omitempty"`
+	Qty        float64 `json:"qty,omitempty"`
+	// SnappedEntryPrice and SnappedQty are EntryPrice/Qty rounded to the
+	// instrument's legal price tick and lot size, filled in by
+	// snapTradePool after the plan is produced. Zero when EntryPrice/Qty
+	// wasn't set.
+	SnappedEntryPrice float64 `json:"snapped_entry_price,omitempty"`
+	SnappedQty        float64 `json:"snapped_qty,omitempty"`
+	// InvalidatePrice is the raw numeric form of Invalidate, for callers
+	// (the market poller's paper-order trigger) that need to compare a
+	// live quote against it instead of parsing the free-text field.
+	// SnappedInvalidatePrice is its tick-rounded counterpart, filled in by
+	// snapTradePool alongside SnappedEntryPrice.
+	InvalidatePrice        float64 `json:"invalidate_price,omitempty"`
+	SnappedInvalidatePrice float64 `json:"snapped_invalidate_price,omitempty"`
 }
 
 type Input struct {
@@ -46,15 +74,23 @@ type Input struct {
 }
 
 type Agent struct {
-	enabled        bool
-	model          *openai.ChatModel
-	modelName      string
-	disabledReason string
+	enabled           bool
+	model             *openai.ChatModel
+	modelName         string
+	disabledReason    string
+	instruments       *market.InstrumentService
+	maxRepairAttempts int
 }
 
-func New(cfg Config) *Agent {
+// New builds a PlanAgent. instruments may be nil, in which case snapped
+// entry prices/quantities are copied from the raw values without rounding.
+func New(cfg Config, instruments *market.InstrumentService) *Agent {
+	maxRepair := cfg.MaxRepairAttempts
+	if maxRepair <= 0 {
+		maxRepair = 2
+	}
 	if !cfg.Enabled {
-		return &Agent{enabled: false, disabledReason: "disabled by config"}
+		return &Agent{enabled: false, disabledReason: "disabled by config", instruments: instruments, maxRepairAttempts: maxRepair}
 	}
 	if cfg.APIKey == "" {
 		cfg.APIKey = os.Getenv("OPENAI_API_KEY")
@@ -67,7 +103,7 @@ func New(cfg Config) *Agent {
 	}
 	if cfg.APIKey == "" || cfg.Model == "" {
 		log.Printf("planagent disabled: missing api key or model")
-		return &Agent{enabled: false, disabledReason: "api_key or model missing"}
+		return &Agent{enabled: false, disabledReason: "api_key or model missing", instruments: instruments, maxRepairAttempts: maxRepair}
 	}
 
 	timeout := time.Duration(cfg.TimeoutMs) * time.Millisecond
@@ -75,51 +111,237 @@ func New(cfg Config) *Agent {
 		timeout = 10 * time.Second
 	}
 
-	model, err := openai.NewChatModel(context.Background(), &openai.ChatModelConfig{
+	modelCfg := &openai.ChatModelConfig{
 		APIKey:     cfg.APIKey,
 		Model:      cfg.Model,
 		BaseURL:    cfg.BaseURL,
 		ByAzure:    cfg.ByAzure,
 		APIVersion: cfg.APIVersion,
 		Timeout:    timeout,
-	})
+	}
+	if supportsJSONSchema(cfg.Model) {
+		modelCfg.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:       "trading_plan",
+				JSONSchema: planJSONSchema(),
+				Strict:     true,
+			},
+		}
+	}
+
+	model, err := openai.NewChatModel(context.Background(), modelCfg)
 	if err != nil {
 		log.Printf("planagent init error: %v", err)
-		return &Agent{enabled: false, disabledReason: "init failed"}
+		return &Agent{enabled: false, disabledReason: "init failed", instruments: instruments, maxRepairAttempts: maxRepair}
 	}
 
-	return &Agent{enabled: true, model: model, modelName: cfg.Model}
+	return &Agent{enabled: true, model: model, modelName: cfg.Model, instruments: instruments, maxRepairAttempts: maxRepair}
+}
+
+// supportsJSONSchema is a best-effort allowlist of model names known to
+// accept response_format: json_schema. The OpenAI API has no capability
+// introspection endpoint, so models outside this list fall back to plain
+// prompting (still parsed by the repair loop in Evaluate).
+func supportsJSONSchema(model string) bool {
+	m := strings.ToLower(model)
+	for _, prefix := range []string{"gpt-4o", "gpt-4.1", "gpt-5", "o1", "o3", "o4"} {
+		if strings.HasPrefix(m, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Instruments returns the instrument catalog this agent snaps trade legs
+// against. Callers that build a Plan without going through Evaluate (e.g.
+// the fallback path, or a backtest replaying a day with no LLM call)
+// should pass this to FallbackPlan so prices snap consistently.
+func (a *Agent) Instruments() *market.InstrumentService {
+	if a == nil {
+		return nil
+	}
+	return a.instruments
 }
 
 func (a *Agent) Evaluate(ctx context.Context, in Input) (Plan, error) {
+	plan, _, err := a.evaluate(ctx, in)
+	return plan, err
+}
+
+// Attempt records one request/response round of the structured-output +
+// repair loop inside Evaluate, for EvaluateWithTrace callers (the operator
+// UI) to debug prompts the model failed to satisfy.
+type Attempt struct {
+	System string `json:"system"`
+	Raw    string `json:"raw"`
+	Error  string `json:"error,omitempty"`
+}
+
+// EvaluateWithTrace behaves like Evaluate but also returns every attempt
+// the repair loop made, in order, including the final one that either
+// succeeded or exhausted cfg.MaxRepairAttempts.
+func (a *Agent) EvaluateWithTrace(ctx context.Context, in Input) (Plan, []Attempt, error) {
+	return a.evaluate(ctx, in)
+}
+
+func planSystemPrompt() string {
+	return `You are PlanAgent. Output ONLY valid JSON.
+Trading style: short-term sentiment A.
+Must include keys: market_bias, max_exposure_pct, trade_pool (array of {symbol,trigger,invalidate,position_pct,stop_loss,entry_price,qty,invalidate_price}), watch_pool, ban_list.
+entry_price, qty and invalidate_price are optional numeric fields (0 if you have none); they will be snapped to the instrument's legal price tick and lot size.
+position_pct must be 0..100. symbol must be one of the symbols in the input quotes. No duplicate symbols in trade_pool.
+No extra text. If uncertain, keep trade_pool empty but still output required keys.`
+}
+
+func (a *Agent) evaluate(ctx context.Context, in Input) (Plan, []Attempt, error) {
 	if !a.enabled || a.model == nil {
-		return FallbackPlan(in), nil
+		return FallbackPlan(in, a.instruments), nil, nil
 	}
 
 	payload, _ := json.Marshal(in)
+	userPrompt := fmt.Sprintf("Input: %s", string(payload))
 
-	system := `You are PlanAgent. Output ONLY valid JSON.
-Trading style: short-term sentiment A.
-Must include keys: market_bias, max_exposure_pct, trade_pool (array of {symbol,trigger,invalidate,position_pct,stop_loss}), watch_pool, ban_list.
-No extra text. If uncertain, keep trade_pool empty but still output required keys.`
+	maxAttempts := a.maxRepairAttempts + 1
+	var attempts []Attempt
+	var lastErr error
+	for i := 0; i < maxAttempts; i++ {
+		system := planSystemPrompt()
+		if i > 0 {
+			prev := attempts[i-1]
+			system += fmt.Sprintf("\n\nYour previous output was invalid: %s\nPrevious output:\n%s\nFix it and return ONLY a JSON object matching the schema above.", prev.Error, prev.Raw)
+		}
+		messages := []*schema.Message{
+			schema.SystemMessage(system),
+			schema.UserMessage(userPrompt),
+		}
 
-	messages := []*schema.Message{
-		schema.SystemMessage(system),
-		schema.UserMessage(fmt.Sprintf("Input: %s", string(payload))),
+		resp, err := a.model.Generate(ctx, messages)
+		if err != nil {
+			logLLMError(err)
+			attempts = append(attempts, Attempt{System: system, Error: err.Error()})
+			return FallbackPlan(in, a.instruments), attempts, err
+		}
+		text := strings.TrimSpace(resp.Content)
+		attempt := Attempt{System: system, Raw: text}
+
+		plan, err := parsePlan(text)
+		if err == nil {
+			if err = validatePlan(plan, in); err == nil {
+				attempts = append(attempts, attempt)
+				return sanitizePlan(plan, a.instruments), attempts, nil
+			}
+		}
+		attempt.Error = err.Error()
+		attempts = append(attempts, attempt)
+		lastErr = err
 	}
+	return FallbackPlan(in, a.instruments), attempts, lastErr
+}
 
-	resp, err := a.model.Generate(ctx, messages)
-	if err != nil {
-		logLLMError(err)
-		return FallbackPlan(in), err
+// validatePlan rejects a parsed Plan that violates invariants the schema
+// alone doesn't enforce: an out-of-range position_pct, a trade_pool symbol
+// the input Quotes never mentioned, or the same symbol listed twice.
+func validatePlan(p Plan, in Input) error {
+	known := knownSymbols(in.Quotes)
+	seen := make(map[string]bool, len(p.TradePool))
+	for _, t := range p.TradePool {
+		if t.PositionPct < 0 || t.PositionPct > 100 {
+			return fmt.Errorf("trade_pool[%s]: position_pct %.2f out of range 0..100", t.Symbol, t.PositionPct)
+		}
+		sym := strings.ToUpper(t.Symbol)
+		if len(known) > 0 && !known[sym] {
+			return fmt.Errorf("trade_pool[%s]: symbol not present in input quotes", t.Symbol)
+		}
+		if seen[sym] {
+			return fmt.Errorf("trade_pool[%s]: duplicate symbol in trade_pool", t.Symbol)
+		}
+		seen[sym] = true
 	}
-	text := strings.TrimSpace(resp.Content)
+	return nil
+}
 
-	plan, err := parsePlan(text)
+// knownSymbols extracts the set of symbols present in an Input's Quotes
+// (any -- typically []market.Quote, but possibly already-decoded JSON), so
+// validatePlan can reject trade_pool entries the input never mentioned.
+func knownSymbols(quotes any) map[string]bool {
+	out := make(map[string]bool)
+	if qs, ok := quotes.([]market.Quote); ok {
+		for _, q := range qs {
+			out[strings.ToUpper(q.Symbol)] = true
+		}
+		return out
+	}
+	data, err := json.Marshal(quotes)
 	if err != nil {
-		return FallbackPlan(in), err
+		return out
+	}
+	var generic []struct {
+		Symbol string `json:"symbol"`
+	}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return out
 	}
-	return sanitizePlan(plan), nil
+	for _, g := range generic {
+		out[strings.ToUpper(g.Symbol)] = true
+	}
+	return out
+}
+
+// stringSchema/numberSchema/arraySchema build the *jsonschema.Schema leaves
+// planJSONSchema is made of; objSchema builds the object nodes, threading
+// properties through an OrderedMap since JSON Schema's "properties" keyword
+// (unlike a plain Go map) is order-sensitive for some strict-mode models.
+func stringSchema() *jsonschema.Schema { return &jsonschema.Schema{Type: "string"} }
+func numberSchema() *jsonschema.Schema { return &jsonschema.Schema{Type: "number"} }
+
+func rangeSchema(min, max float64) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:    "number",
+		Minimum: json.Number(strconv.FormatFloat(min, 'f', -1, 64)),
+		Maximum: json.Number(strconv.FormatFloat(max, 'f', -1, 64)),
+	}
+}
+
+func arraySchema(items *jsonschema.Schema) *jsonschema.Schema {
+	return &jsonschema.Schema{Type: "array", Items: items}
+}
+
+func objSchema(required []string, props ...orderedmap.Pair[string, *jsonschema.Schema]) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:       "object",
+		Properties: orderedmap.New[string, *jsonschema.Schema](orderedmap.WithInitialData(props...)),
+		Required:   required,
+	}
+}
+
+func prop(key string, s *jsonschema.Schema) orderedmap.Pair[string, *jsonschema.Schema] {
+	return orderedmap.Pair[string, *jsonschema.Schema]{Key: key, Value: s}
+}
+
+// planJSONSchema is Plan's wire shape as a JSON Schema, used both as the
+// response_format schema for models that support structured output and
+// as context for models that don't (via planSystemPrompt's description).
+func planJSONSchema() *jsonschema.Schema {
+	tradeItem := objSchema(
+		[]string{"symbol"},
+		prop("symbol", stringSchema()),
+		prop("trigger", stringSchema()),
+		prop("invalidate", stringSchema()),
+		prop("position_pct", rangeSchema(0, 100)),
+		prop("stop_loss", stringSchema()),
+		prop("entry_price", numberSchema()),
+		prop("qty", numberSchema()),
+		prop("invalidate_price", numberSchema()),
+	)
+	return objSchema(
+		[]string{"market_bias", "max_exposure_pct", "trade_pool", "watch_pool", "ban_list"},
+		prop("market_bias", stringSchema()),
+		prop("max_exposure_pct", numberSchema()),
+		prop("trade_pool", arraySchema(tradeItem)),
+		prop("watch_pool", arraySchema(stringSchema())),
+		prop("ban_list", arraySchema(stringSchema())),
+	)
 }
 
 func Ping(a *Agent, ctx context.Context) (map[string]any, error) {
@@ -144,11 +366,13 @@ func Ping(a *Agent, ctx context.Context) (map[string]any, error) {
 	return map[string]any{"ok": true, "mode": "llm", "model": a.modelName, "latency_ms": latency}, nil
 }
 
-func FallbackPlan(in Input) Plan {
+// FallbackPlan builds the deterministic, no-LLM plan used when PlanAgent is
+// disabled or errors out. instruments may be nil.
+func FallbackPlan(in Input, instruments *market.InstrumentService) Plan {
 	return Plan{
 		MarketBias:     "neutral",
 		MaxExposurePct: 30,
-		TradePool:      []TradeItem{},
+		TradePool:      snapTradePool(nil, instruments),
 		WatchPool:      []string{},
 		BanList:        []string{"高波动消息驱动"},
 	}
@@ -189,7 +413,7 @@ func extractFirstJSONObject(s string) string {
 	return ""
 }
 
-func sanitizePlan(p Plan) Plan {
+func sanitizePlan(p Plan, instruments *market.InstrumentService) Plan {
 	if p.MarketBias == "" {
 		p.MarketBias = "neutral"
 	}
@@ -199,9 +423,7 @@ func sanitizePlan(p Plan) Plan {
 	if p.MaxExposurePct > 100 {
 		p.MaxExposurePct = 100
 	}
-	if p.TradePool == nil {
-		p.TradePool = []TradeItem{}
-	}
+	p.TradePool = snapTradePool(p.TradePool, instruments)
 	if p.WatchPool == nil {
 		p.WatchPool = []string{}
 	}
@@ -211,6 +433,39 @@ func sanitizePlan(p Plan) Plan {
 	return p
 }
 
+// snapTradePool fills in SnappedEntryPrice/SnappedQty for every leg that
+// carries a raw EntryPrice/Qty, rounding each to the instrument's legal
+// price tick and lot size. Legs without a raw value are left at zero. With
+// nil instruments the snapped fields are the raw values, unrounded.
+func snapTradePool(items []TradeItem, instruments *market.InstrumentService) []TradeItem {
+	out := make([]TradeItem, len(items))
+	for i, item := range items {
+		if item.EntryPrice > 0 {
+			if instruments != nil {
+				item.SnappedEntryPrice = instruments.RoundPrice(item.Symbol, item.EntryPrice)
+			} else {
+				item.SnappedEntryPrice = item.EntryPrice
+			}
+		}
+		if item.Qty > 0 {
+			if instruments != nil {
+				item.SnappedQty = instruments.RoundQty(item.Symbol, item.Qty)
+			} else {
+				item.SnappedQty = item.Qty
+			}
+		}
+		if item.InvalidatePrice > 0 {
+			if instruments != nil {
+				item.SnappedInvalidatePrice = instruments.RoundPrice(item.Symbol, item.InvalidatePrice)
+			} else {
+				item.SnappedInvalidatePrice = item.InvalidatePrice
+			}
+		}
+		out[i] = item
+	}
+	return out
+}
+
 func logLLMError(err error) {
 	apiErr := &openai.APIError{}
 	if errors.As(err, &apiErr) {
@@ -10,12 +10,15 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
+
+	"ai-trading-assistant/internal/metrics"
 )
 
 type Client struct {
-	webhook   string
-	secret    string
+	webhook    string
+	secret     string
 	httpClient *http.Client
 }
 
@@ -65,16 +68,21 @@ func (c *Client) SendMarkdown(ctx context.Context, title, markdown string) (*Res
 	}
 	req.Header.Set("Content-Type", "application/json")
 
+	sendStart := time.Now()
 	resp, err := c.httpClient.Do(req)
+	metrics.DingTalkSendDuration.Observe(time.Since(sendStart).Seconds())
 	if err != nil {
+		metrics.DingTalkErrCodeTotal.WithLabelValues("transport_error").Inc()
 		return nil, fmt.Errorf("http request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	var out Response
 	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		metrics.DingTalkErrCodeTotal.WithLabelValues("decode_error").Inc()
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
+	metrics.DingTalkErrCodeTotal.WithLabelValues(strconv.Itoa(out.ErrCode)).Inc()
 
 	return &out, nil
 }
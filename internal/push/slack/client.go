@@ -0,0 +1,65 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Client posts a blocks message to a Slack incoming webhook.
+type Client struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+func NewClient(webhookURL string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &Client{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Send posts title/markdown as a Slack blocks message and returns the HTTP
+// status code. A status >= 300 is not turned into an error here: callers
+// decide for themselves what counts as a failed send.
+func (c *Client) Send(ctx context.Context, title, markdown string) (status int, err error) {
+	if c.webhookURL == "" {
+		return 0, fmt.Errorf("slack webhook url is empty")
+	}
+
+	payload := map[string]any{
+		"blocks": []map[string]any{
+			{
+				"type": "header",
+				"text": map[string]any{"type": "plain_text", "text": title},
+			},
+			{
+				"type": "section",
+				"text": map[string]any{"type": "mrkdwn", "text": markdown},
+			},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Client POSTs a caller-built JSON body to a user-supplied URL. It's the
+// transport used by the webhook escape-hatch channel in both internal/alert
+// and internal/notifier; each package builds its own payload shape and
+// hands the marshaled body to Post.
+type Client struct {
+	url        string
+	secret     string
+	httpClient *http.Client
+}
+
+func NewClient(url, secret string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &Client{
+		url:        url,
+		secret:     secret,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Post sends body as-is, signing it with an X-Signature header when a
+// secret is configured, and returns the HTTP status code. A status >= 300
+// is not turned into an error here: callers decide for themselves what
+// counts as a failed send.
+func (c *Client) Post(ctx context.Context, body []byte) (status int, err error) {
+	if c.url == "" {
+		return 0, fmt.Errorf("webhook url is empty")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.secret != "" {
+		req.Header.Set("X-Signature", sign(body, c.secret))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// sign signs the raw request body with HMAC-SHA256 keyed by secret and
+// returns the hex digest. Unlike push/dingtalk.sign, which signs a
+// timestamp string and base64-encodes the result, it covers the body itself.
+func sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -10,6 +10,8 @@ import (
 	"strings"
 	"time"
 
+	"ai-trading-assistant/internal/metrics"
+
 	"github.com/cloudwego/eino-ext/components/model/openai"
 	"github.com/cloudwego/eino/schema"
 )
@@ -33,6 +35,13 @@ type EventInput struct {
 	DrawdownPct float64 `json:"drawdown_pct,omitempty"`
 	WindowSec   int     `json:"window_sec,omitempty"`
 	Evidence    string  `json:"evidence_json,omitempty"`
+
+	// Derivatives-only fields, populated when the triggering snapshot came
+	// from a FuturesProvider. Zero value means "not a contract event".
+	MarkPrice              float64 `json:"mark_price,omitempty"`
+	IndexPrice             float64 `json:"index_price,omitempty"`
+	FundingRate            float64 `json:"funding_rate,omitempty"`
+	LiquidationDistancePct float64 `json:"liquidation_distance_pct,omitempty"`
 }
 
 type RiskDecision struct {
@@ -132,6 +141,11 @@ func (a *Agent) Evaluate(ctx context.Context, in EventInput) (RiskDecision, erro
 		return FallbackDecision(in), nil
 	}
 
+	start := time.Now()
+	defer func() {
+		metrics.RiskAgentEvalDuration.WithLabelValues("llm").Observe(time.Since(start).Seconds())
+	}()
+
 	payload, _ := json.Marshal(in)
 
 	system := `你是 RiskAgent。你必须只输出合法 JSON。
@@ -190,6 +204,13 @@ func FallbackDecision(in EventInput) RiskDecision {
 	return fallbackFromEvent(in)
 }
 
+// ParseDecisionText exposes the LLM-output parsing path (code-fenced JSON,
+// prose-prefixed JSON, brace scanning) so conformance harnesses can assert
+// on it without spinning up a real Agent.
+func ParseDecisionText(text string) (RiskDecision, error) {
+	return parseRiskDecision(text)
+}
+
 func sanitize(in RiskDecision) RiskDecision {
 	out := in
 	if out.RiskLevel < 1 {
@@ -366,6 +387,28 @@ func buildWhyAction(in EventInput) ([]string, []string) {
 			action := []string{"降低整体仓位上限", "减少高位追涨，优先防守"}
 			return why, action
 		}
+	case "FUNDING_RISK":
+		divergencePct := 0.0
+		if in.IndexPrice != 0 {
+			divergencePct = (in.MarkPrice - in.IndexPrice) / in.IndexPrice * 100
+		}
+		why := []string{fmt.Sprintf("资金费率 %.4f%%，标记价与指数价偏离 %.2f%%", in.FundingRate*100, divergencePct)}
+		action := []string{"警惕费率拥挤方向的反向挤仓", "控制杠杆与持仓周期"}
+		return why, action
+	case "LIQUIDATION_RISK":
+		why := []string{fmt.Sprintf("预估距强平价差 %.2f%%，标记价 %.4f", in.LiquidationDistancePct, in.MarkPrice)}
+		action := []string{"优先降杠杆/追加保证金", "设置强平前止损，避免被动平仓"}
+		return why, action
+	case "NARROW_RANGE":
+		n := getFloat(ev["n"])
+		rng := getFloat(ev["range"])
+		avgRange := getFloat(ev["avg_range_20"])
+		atr14 := getFloat(ev["atr14"])
+		up := getFloat(ev["breakout_level_up"])
+		down := getFloat(ev["breakout_level_down"])
+		why := []string{fmt.Sprintf("NR%d窄幅整理，波幅 %.4f（20周期均幅 %.4f，ATR14 %.4f）", int(n), rng, avgRange, atr14)}
+		action := []string{fmt.Sprintf("关注上破 %.4f 或下破 %.4f 方向选择", up, down), "整理阶段轻仓观望，等待方向确认"}
+		return why, action
 	}
 	why := buildGenericWhy(in)
 	action := buildActionFromSeverity(strings.ToLower(in.Severity))
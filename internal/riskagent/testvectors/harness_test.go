@@ -0,0 +1,31 @@
+package testvectors
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func testdataDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "testdata", "vectors")
+}
+
+func TestFallbackVectors(t *testing.T) {
+	vectors, err := LoadDir(testdataDir())
+	if err != nil {
+		t.Fatalf("LoadDir: %v", err)
+	}
+
+	r := NewRunner(nil)
+	for _, res := range r.RunFallback(vectors) {
+		if !res.Passed {
+			t.Errorf("vector %q failed: %v (decision=%+v)", res.Name, res.Failures, res.Decision)
+		}
+	}
+	for _, res := range r.RunParse(vectors) {
+		if !res.Passed {
+			t.Errorf("vector %q failed: %v", res.Name, res.Failures)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package testvectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadDir reads every *.yaml/*.yml/*.json file under dir and returns the
+// vectors in filename order.
+func LoadDir(dir string) ([]Vector, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read vectors dir: %w", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+
+	var out []Vector
+	for _, name := range names {
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+			continue
+		}
+		vecs, err := loadFile(filepath.Join(dir, name), ext)
+		if err != nil {
+			return nil, fmt.Errorf("load %s: %w", name, err)
+		}
+		out = append(out, vecs...)
+	}
+	return out, nil
+}
+
+func loadFile(path, ext string) ([]Vector, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var vecs []Vector
+	if ext == ".json" {
+		if err := json.Unmarshal(data, &vecs); err != nil {
+			return nil, err
+		}
+		return vecs, nil
+	}
+	if err := yaml.Unmarshal(data, &vecs); err != nil {
+		return nil, err
+	}
+	return vecs, nil
+}
@@ -0,0 +1,224 @@
+package testvectors
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"ai-trading-assistant/internal/riskagent"
+)
+
+// Result is the outcome of running one Vector.
+type Result struct {
+	Name        string
+	Passed      bool
+	Failures    []string
+	Decision    riskagent.RiskDecision
+	ParseErr    error
+	LatencyMs   float64
+	ASCIILeaked bool
+}
+
+// Runner executes vectors against the deterministic fallback path and,
+// optionally, a live Agent.
+type Runner struct {
+	Agent *riskagent.Agent
+}
+
+// NewRunner builds a Runner. agent may be nil to restrict execution to the
+// fallback/parse-only paths.
+func NewRunner(agent *riskagent.Agent) *Runner {
+	return &Runner{Agent: agent}
+}
+
+// RunFallback runs every vector whose Input is set against
+// riskagent.FallbackDecision and checks its Expectation.
+func (r *Runner) RunFallback(vectors []Vector) []Result {
+	var out []Result
+	for _, v := range vectors {
+		if v.Input == nil {
+			continue
+		}
+		start := time.Now()
+		decision := riskagent.FallbackDecision(*v.Input)
+		out = append(out, check(v, decision, nil, time.Since(start)))
+	}
+	return out
+}
+
+// RunParse runs every vector whose RawLLMText is set against
+// riskagent.ParseDecisionText, exercising the JSON-repair path directly.
+func (r *Runner) RunParse(vectors []Vector) []Result {
+	var out []Result
+	for _, v := range vectors {
+		if v.RawLLMText == "" {
+			continue
+		}
+		start := time.Now()
+		decision, err := riskagent.ParseDecisionText(v.RawLLMText)
+		elapsed := time.Since(start)
+
+		if v.ExpectParse && err != nil {
+			out = append(out, Result{
+				Name:      v.Name,
+				Passed:    false,
+				Failures:  []string{fmt.Sprintf("expected parse to succeed, got error: %v", err)},
+				ParseErr:  err,
+				LatencyMs: float64(elapsed.Microseconds()) / 1000,
+			})
+			continue
+		}
+		if !v.ExpectParse && err == nil {
+			out = append(out, Result{
+				Name:      v.Name,
+				Passed:    false,
+				Failures:  []string{"expected parse to fail, but it succeeded"},
+				Decision:  decision,
+				LatencyMs: float64(elapsed.Microseconds()) / 1000,
+			})
+			continue
+		}
+		if err != nil {
+			out = append(out, Result{Name: v.Name, Passed: true, ParseErr: err, LatencyMs: float64(elapsed.Microseconds()) / 1000})
+			continue
+		}
+		out = append(out, check(v, decision, nil, elapsed))
+	}
+	return out
+}
+
+// RunLive runs every vector whose Input is set against the live Agent's
+// Evaluate method. It is a no-op (returns nil) when r.Agent is nil; a
+// disabled Agent's Evaluate answers from FallbackDecision, so its results
+// match RunFallback's.
+func (r *Runner) RunLive(vectors []Vector) []Result {
+	if r.Agent == nil {
+		return nil
+	}
+	var out []Result
+	for _, v := range vectors {
+		if v.Input == nil {
+			continue
+		}
+		start := time.Now()
+		decision, err := r.Agent.Evaluate(context.Background(), *v.Input)
+		out = append(out, check(v, decision, err, time.Since(start)))
+	}
+	return out
+}
+
+func check(v Vector, decision riskagent.RiskDecision, evalErr error, elapsed time.Duration) Result {
+	res := Result{
+		Name:      v.Name,
+		Decision:  decision,
+		LatencyMs: float64(elapsed.Microseconds()) / 1000,
+		Passed:    true,
+	}
+	exp := v.Expect
+
+	if len(exp.SeverityOneOf) > 0 && !contains(exp.SeverityOneOf, decision.Severity) {
+		res.fail(fmt.Sprintf("severity %q not in %v", decision.Severity, exp.SeverityOneOf))
+	}
+	if len(exp.RiskLevelOneOf) > 0 && !containsInt(exp.RiskLevelOneOf, decision.RiskLevel) {
+		res.fail(fmt.Sprintf("risk_level %d not in %v", decision.RiskLevel, exp.RiskLevelOneOf))
+	}
+	if exp.ConfidenceMin > 0 && decision.Confidence < exp.ConfidenceMin {
+		res.fail(fmt.Sprintf("confidence %.2f below min %.2f", decision.Confidence, exp.ConfidenceMin))
+	}
+	if exp.ConfidenceMax > 0 && decision.Confidence > exp.ConfidenceMax {
+		res.fail(fmt.Sprintf("confidence %.2f above max %.2f", decision.Confidence, exp.ConfidenceMax))
+	}
+	if exp.OneLinerRegex != "" && !matches(exp.OneLinerRegex, decision.OneLiner) {
+		res.fail(fmt.Sprintf("one_liner %q does not match %q", decision.OneLiner, exp.OneLinerRegex))
+	}
+	if exp.WhyRegex != "" && !matchesAny(exp.WhyRegex, decision.Why) {
+		res.fail(fmt.Sprintf("why %v does not match %q", decision.Why, exp.WhyRegex))
+	}
+	if exp.ActionHintRegex != "" && !matchesAny(exp.ActionHintRegex, decision.ActionHint) {
+		res.fail(fmt.Sprintf("action_hint %v does not match %q", decision.ActionHint, exp.ActionHintRegex))
+	}
+	if exp.RequireASCIIFree {
+		if leaked := asciiLeak(decision); leaked != "" {
+			res.ASCIILeaked = true
+			res.fail(fmt.Sprintf("ascii leaked in %q", leaked))
+		}
+	}
+	if evalErr != nil {
+		res.fail(fmt.Sprintf("agent.Evaluate error: %v", evalErr))
+	}
+	return res
+}
+
+func (r *Result) fail(msg string) {
+	r.Passed = false
+	r.Failures = append(r.Failures, msg)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(list []int, n int) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(pattern, s string) bool {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(s)
+}
+
+func matchesAny(pattern string, list []string) bool {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false
+	}
+	for _, s := range list {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func asciiLeak(decision riskagent.RiskDecision) string {
+	if isASCIIHeavy(decision.OneLiner) {
+		return decision.OneLiner
+	}
+	for _, w := range decision.Why {
+		if isASCIIHeavy(w) {
+			return w
+		}
+	}
+	for _, a := range decision.ActionHint {
+		if isASCIIHeavy(a) {
+			return a
+		}
+	}
+	return ""
+}
+
+// isASCIIHeavy flags strings that look like un-translated English prose
+// rather than a stray ticker symbol or number embedded in Chinese text.
+func isASCIIHeavy(s string) bool {
+	letters := 0
+	for _, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			letters++
+		}
+	}
+	return letters >= 8
+}
@@ -0,0 +1,31 @@
+// Package testvectors is a conformance harness for riskagent.Agent: it loads
+// fixtures describing an EventInput (or raw LLM output text) and the
+// properties the resulting RiskDecision must satisfy, then runs them against
+// FallbackDecision and/or a live Agent.
+package testvectors
+
+import "ai-trading-assistant/internal/riskagent"
+
+// Vector is one fixture. Either Input or RawLLMText should be set:
+// Input drives Agent.Evaluate/FallbackDecision, RawLLMText exercises the
+// JSON-repair parsing path directly via riskagent.ParseDecisionText.
+type Vector struct {
+	Name        string                `json:"name" yaml:"name"`
+	Input       *riskagent.EventInput `json:"input,omitempty" yaml:"input,omitempty"`
+	RawLLMText  string                `json:"raw_llm_text,omitempty" yaml:"raw_llm_text,omitempty"`
+	ExpectParse bool                  `json:"expect_parse" yaml:"expect_parse"`
+	Expect      Expectation           `json:"expect" yaml:"expect"`
+}
+
+// Expectation describes tolerances on a RiskDecision. Empty slices/zero
+// bounds mean "don't check this field".
+type Expectation struct {
+	SeverityOneOf    []string `json:"severity_one_of,omitempty" yaml:"severity_one_of,omitempty"`
+	RiskLevelOneOf   []int    `json:"risk_level_one_of,omitempty" yaml:"risk_level_one_of,omitempty"`
+	ConfidenceMin    float64  `json:"confidence_min,omitempty" yaml:"confidence_min,omitempty"`
+	ConfidenceMax    float64  `json:"confidence_max,omitempty" yaml:"confidence_max,omitempty"`
+	OneLinerRegex    string   `json:"one_liner_regex,omitempty" yaml:"one_liner_regex,omitempty"`
+	WhyRegex         string   `json:"why_regex,omitempty" yaml:"why_regex,omitempty"`
+	ActionHintRegex  string   `json:"action_hint_regex,omitempty" yaml:"action_hint_regex,omitempty"`
+	RequireASCIIFree bool     `json:"require_ascii_free,omitempty" yaml:"require_ascii_free,omitempty"`
+}
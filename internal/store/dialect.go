@@ -0,0 +1,88 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// dialect hides the handful of places a SQL backend choice actually shows
+// up in this package: connection bootstrapping (SQLite's WAL/busy-timeout
+// PRAGMAs have no Postgres equivalent), placeholder syntax (the queries in
+// sqlite.go are written with SQLite/MySQL-style "?"; Postgres needs
+// positional "$1, $2, ..."), and which migrations/<dialect> folder to load
+// schema changes from. Everything else - the AlertRecord/EventRecord/
+// MarketSnapshot/PlanRecord types and the query/business logic around them
+// - is dialect-agnostic.
+type dialect interface {
+	name() string
+	migrationsDir() string
+	init(db *sql.DB) error
+	rebind(query string) string
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) name() string          { return "sqlite" }
+func (sqliteDialect) migrationsDir() string { return "migrations/sqlite" }
+
+func (sqliteDialect) init(db *sql.DB) error {
+	if _, err := db.Exec("PRAGMA journal_mode=WAL;"); err != nil {
+		return fmt.Errorf("pragma wal: %w", err)
+	}
+	if _, err := db.Exec("PRAGMA busy_timeout=3000;"); err != nil {
+		return fmt.Errorf("pragma busy_timeout: %w", err)
+	}
+	return nil
+}
+
+// rebind is a no-op: database/sql's sqlite driver accepts "?" as-is.
+func (sqliteDialect) rebind(query string) string { return query }
+
+type postgresDialect struct{}
+
+func (postgresDialect) name() string          { return "postgres" }
+func (postgresDialect) migrationsDir() string { return "migrations/postgres" }
+
+// init is a no-op: Postgres has no equivalent of SQLite's WAL/busy-timeout
+// PRAGMAs, and pgx's connection pool handles contention on its own.
+func (postgresDialect) init(db *sql.DB) error { return nil }
+
+// rebind rewrites every "?" to a sequential "$1", "$2", ... placeholder,
+// the only form Postgres accepts.
+func (postgresDialect) rebind(query string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		n++
+		b.WriteByte('$')
+		b.WriteString(strconv.Itoa(n))
+	}
+	return b.String()
+}
+
+// parseDSN splits dsn into the dialect it selects and the driver-specific
+// connection string sql.Open needs. A bare filesystem path with no
+// "scheme://" (what every existing config.yaml sets via store.sqlite.path)
+// is treated as a SQLite path, so existing deployments don't need to
+// change anything.
+func parseDSN(dsn string) (dialect, string, error) {
+	if dsn == "" {
+		dsn = "data/app.db"
+	}
+	switch {
+	case strings.HasPrefix(dsn, "sqlite://"):
+		return sqliteDialect{}, strings.TrimPrefix(dsn, "sqlite://"), nil
+	case strings.HasPrefix(dsn, "postgres://"), strings.HasPrefix(dsn, "postgresql://"):
+		return postgresDialect{}, dsn, nil
+	case strings.Contains(dsn, "://"):
+		return nil, "", fmt.Errorf("unsupported store dsn scheme: %s", dsn)
+	default:
+		return sqliteDialect{}, dsn, nil
+	}
+}
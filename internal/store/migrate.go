@@ -0,0 +1,321 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/sqlite/*.sql migrations/postgres/*.sql
+var migrationsFS embed.FS
+
+// Direction selects which way Migrate walks the migration chain.
+type Direction string
+
+const (
+	DirectionUp   Direction = "up"
+	DirectionDown Direction = "down"
+)
+
+// migration is one numbered schema change, loaded from a pair of
+// NNNNNN_name.up.sql / NNNNNN_name.down.sql files under the dialect's
+// migrations/<dialect> folder.
+type migration struct {
+	version int
+	name    string
+	upSQL   string
+	downSQL string
+}
+
+// MigrationStatus reports one migration's version/name and whether it has
+// been applied, for the "store migrate status" CLI subcommand.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt string
+}
+
+func loadMigrations(dia dialect) ([]migration, error) {
+	dir := dia.migrationsDir()
+	entries, err := migrationsFS.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations dir: %w", err)
+	}
+
+	byVersion := make(map[int]*migration)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+		version, label, err := parseMigrationFilename(name)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := migrationsFS.ReadFile(dir + "/" + name)
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", name, err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version, name: label}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.upSQL = string(data)
+		} else {
+			m.downSQL = string(data)
+		}
+	}
+
+	out := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.upSQL == "" {
+			return nil, fmt.Errorf("migration %06d_%s is missing its .up.sql file", m.version, m.name)
+		}
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].version < out[j].version })
+	return out, nil
+}
+
+// parseMigrationFilename splits "000001_init.up.sql" into (1, "init").
+func parseMigrationFilename(name string) (int, string, error) {
+	base := strings.TrimSuffix(strings.TrimSuffix(name, ".up.sql"), ".down.sql")
+	parts := strings.SplitN(base, "_", 2)
+	if len(parts) != 2 {
+		return 0, "", fmt.Errorf("invalid migration filename: %s", name)
+	}
+	version, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid migration version in filename %s: %w", name, err)
+	}
+	return version, parts[1], nil
+}
+
+// ensureMigrationsTable creates schema_migrations if it doesn't exist yet,
+// and backfills version 1 when the database already has the tables that
+// 000001_init used to create inline (i.e. it predates this migrator), so
+// it isn't re-run against a database that already has them.
+func (s *Store) ensureMigrationsTable(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TEXT
+	);`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	var count int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations`).Scan(&count); err != nil {
+		return fmt.Errorf("count schema_migrations: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	preexisting, err := s.tableExists(ctx, "alerts")
+	if err != nil {
+		return fmt.Errorf("check preexisting schema: %w", err)
+	}
+	if !preexisting {
+		return nil
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		s.dialect.rebind(`INSERT INTO schema_migrations (version, applied_at) VALUES (1, ?)`),
+		time.Now().Format(time.RFC3339),
+	); err != nil {
+		return fmt.Errorf("backfill schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// tableExists reports whether table already exists, using each dialect's
+// own information-schema/catalog so the check works before the new-install
+// vs. predates-the-migrator decision in ensureMigrationsTable is made.
+func (s *Store) tableExists(ctx context.Context, table string) (bool, error) {
+	query := `SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name=?`
+	if s.dialect.name() == "postgres" {
+		query = `SELECT COUNT(*) FROM information_schema.tables WHERE table_name=$1`
+	}
+	var count int
+	if err := s.db.QueryRowContext(ctx, query, table).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (s *Store) appliedVersions(ctx context.Context) (map[int]bool, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return nil, fmt.Errorf("scan schema_migrations: %w", err)
+		}
+		applied[v] = true
+	}
+	return applied, rows.Err()
+}
+
+// Migrate applies (DirectionUp) every migration newer than the database's
+// current version, or reverts (DirectionDown) the single most recently
+// applied migration. Each migration runs inside its own transaction that
+// also records/removes the schema_migrations row, so a failed migration
+// never leaves the version table out of sync with the schema.
+func (s *Store) Migrate(ctx context.Context, direction Direction) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("store not configured")
+	}
+	if err := s.ensureMigrationsTable(ctx); err != nil {
+		return err
+	}
+
+	migrations, err := loadMigrations(s.dialect)
+	if err != nil {
+		return err
+	}
+	applied, err := s.appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch direction {
+	case DirectionUp:
+		for _, m := range migrations {
+			if applied[m.version] {
+				continue
+			}
+			if err := s.runMigration(ctx, m.version, m.upSQL, func(tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx,
+					s.dialect.rebind(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`),
+					m.version, time.Now().Format(time.RFC3339),
+				)
+				return err
+			}); err != nil {
+				return fmt.Errorf("migrate up to version %d: %w", m.version, err)
+			}
+		}
+		return nil
+	case DirectionDown:
+		var target *migration
+		for i := len(migrations) - 1; i >= 0; i-- {
+			if applied[migrations[i].version] {
+				target = &migrations[i]
+				break
+			}
+		}
+		if target == nil {
+			return nil
+		}
+		if err := s.runMigration(ctx, target.version, target.downSQL, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, s.dialect.rebind(`DELETE FROM schema_migrations WHERE version = ?`), target.version)
+			return err
+		}); err != nil {
+			return fmt.Errorf("migrate down from version %d: %w", target.version, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migration direction: %s", direction)
+	}
+}
+
+// runMigration executes sqlStatements plus bookkeeping inside one
+// transaction. sqlite's driver doesn't support multiple statements per
+// Exec, so the file is split on every ";".
+func (s *Store) runMigration(ctx context.Context, version int, sqlStatements string, bookkeeping func(*sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range splitStatements(sqlStatements) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("exec statement: %w", err)
+		}
+	}
+	if err := bookkeeping(tx); err != nil {
+		return fmt.Errorf("record migration: %w", err)
+	}
+	return tx.Commit()
+}
+
+func splitStatements(sqlText string) []string {
+	var out []string
+	for _, raw := range strings.Split(sqlText, ";") {
+		stmt := strings.TrimSpace(raw)
+		if stmt != "" {
+			out = append(out, stmt)
+		}
+	}
+	return out
+}
+
+// MigrationStatus reports every known migration and whether it has been
+// applied, in ascending version order.
+func (s *Store) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("store not configured")
+	}
+	if err := s.ensureMigrationsTable(ctx); err != nil {
+		return nil, err
+	}
+
+	migrations, err := loadMigrations(s.dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+	appliedAt := make(map[int]string)
+	for rows.Next() {
+		var v int
+		var at string
+		if err := rows.Scan(&v, &at); err != nil {
+			return nil, fmt.Errorf("scan schema_migrations: %w", err)
+		}
+		appliedAt[v] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		at, ok := appliedAt[m.version]
+		out = append(out, MigrationStatus{
+			Version:   m.version,
+			Name:      m.name,
+			Applied:   ok,
+			AppliedAt: at,
+		})
+	}
+	return out, nil
+}
@@ -1,17 +1,20 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
+	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "modernc.org/sqlite"
 )
 
 type Store struct {
-	db *sql.DB
+	db      *sql.DB
+	dialect dialect
 }
 
 type AlertRecord struct {
@@ -45,6 +48,7 @@ type EventRecord struct {
 type MarketSnapshot struct {
 	TS        int64   `json:"ts"`
 	Symbol    string  `json:"symbol"`
+	Name      string  `json:"name"`
 	Price     float64 `json:"price"`
 	ChangePct float64 `json:"change_pct"`
 	Volume    float64 `json:"volume"`
@@ -60,31 +64,116 @@ type PlanRecord struct {
 	CreatedAt   string `json:"created_at"`
 }
 
-func Open(path string) (*Store, error) {
-	if path == "" {
-		path = "data/app.db"
+// NotifierDeliveryRecord logs a single attempt to push a rendered message to
+// an external notification channel, so failed sends can be audited/retried.
+type NotifierDeliveryRecord struct {
+	TS        int64  `json:"ts"`
+	Channel   string `json:"channel"`
+	DedupKey  string `json:"dedup_key"`
+	Title     string `json:"title"`
+	Payload   string `json:"payload"`
+	Status    string `json:"status"`
+	Error     string `json:"error"`
+	CreatedAt string `json:"created_at"`
+}
+
+// AlertWALRecord is a write-ahead-log row for a pending merge batch or
+// digest entry. alert.Service persists one row per enqueued alert so a
+// crash between enqueue and flush doesn't silently drop it: on startup it
+// replays every row still in the table, reconstructing the in-memory merge
+// timer / digest group it would have had before the restart.
+type AlertWALRecord struct {
+	ID         int64  `json:"id"`
+	Kind       string `json:"kind"` // "merge" or "digest"
+	Key        string `json:"key"`
+	GroupName  string `json:"group"`
+	ReqJSON    string `json:"req_json"`
+	EnqueuedAt int64  `json:"enqueued_at"`
+	FlushAt    int64  `json:"flush_at"`
+}
+
+// OrderRecord is a paper (or, eventually, live) order placed against a
+// plan's TradeItem. Broker+ExtID uniquely identify the order so repeated
+// triggers (e.g. the market poller re-checking the same price crossing on
+// every tick) don't insert duplicates.
+type OrderRecord struct {
+	ID              int64   `json:"id"`
+	TS              int64   `json:"ts"`
+	PlanDate        string  `json:"plan_date"`
+	TradeItemSymbol string  `json:"trade_item_symbol"`
+	Symbol          string  `json:"symbol"`
+	Side            string  `json:"side"`
+	Qty             float64 `json:"qty"`
+	Price           float64 `json:"price"`
+	Status          string  `json:"status"`
+	Broker          string  `json:"broker"`
+	ExtID           string  `json:"ext_id"`
+	CreatedAt       string  `json:"created_at"`
+}
+
+// ExecutionRecord is a fill against an OrderRecord. Like OrderRecord,
+// Broker+ExtID dedupes repeated fill reports for the same trade.
+type ExecutionRecord struct {
+	ID        int64   `json:"id"`
+	OrderID   int64   `json:"order_id"`
+	TS        int64   `json:"ts"`
+	Symbol    string  `json:"symbol"`
+	Side      string  `json:"side"`
+	Qty       float64 `json:"qty"`
+	Price     float64 `json:"price"`
+	Broker    string  `json:"broker"`
+	ExtID     string  `json:"ext_id"`
+	CreatedAt string  `json:"created_at"`
+}
+
+// PositionRecord is the running net position for a symbol, maintained by
+// RecordExecution as fills come in.
+type PositionRecord struct {
+	Symbol      string  `json:"symbol"`
+	Qty         float64 `json:"qty"`
+	AvgPrice    float64 `json:"avg_price"`
+	RealizedPnl float64 `json:"realized_pnl"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+// Open connects to the store backend named by dsn. dsn is one of:
+//
+//   - a bare filesystem path (e.g. "data/app.db"), or "sqlite://<path>" /
+//     "sqlite:///<absolute path>" - opens a SQLite database at that path
+//   - "postgres://user:pw@host/db" / "postgresql://..." - opens a Postgres
+//     database via pgx
+//
+// Either way, the resulting Store exposes the same AlertRecord/EventRecord/
+// MarketSnapshot/PlanRecord API; callers never see which backend is live.
+func Open(dsn string) (*Store, error) {
+	dia, connStr, err := parseDSN(dsn)
+	if err != nil {
+		return nil, err
 	}
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return nil, fmt.Errorf("create db dir: %w", err)
+
+	driverName := "sqlite"
+	if dia.name() == "sqlite" {
+		if err := os.MkdirAll(filepath.Dir(connStr), 0o755); err != nil {
+			return nil, fmt.Errorf("create db dir: %w", err)
+		}
+	} else {
+		driverName = "pgx"
 	}
-	db, err := sql.Open("sqlite", path)
+
+	db, err := sql.Open(driverName, connStr)
 	if err != nil {
-		return nil, fmt.Errorf("open sqlite: %w", err)
+		return nil, fmt.Errorf("open %s: %w", dia.name(), err)
 	}
-	if _, err := db.Exec("PRAGMA journal_mode=WAL;"); err != nil {
+	if err := dia.init(db); err != nil {
 		_ = db.Close()
-		return nil, fmt.Errorf("pragma wal: %w", err)
-	}
-	if _, err := db.Exec("PRAGMA busy_timeout=3000;"); err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("pragma busy_timeout: %w", err)
+		return nil, err
 	}
 	if err := db.Ping(); err != nil {
 		_ = db.Close()
-		return nil, fmt.Errorf("ping sqlite: %w", err)
+		return nil, fmt.Errorf("ping %s: %w", dia.name(), err)
 	}
-	store := &Store{db: db}
-	if err := store.migrate(); err != nil {
+	store := &Store{db: db, dialect: dia}
+	if err := store.Migrate(context.Background(), DirectionUp); err != nil {
 		_ = db.Close()
 		return nil, err
 	}
@@ -98,67 +187,16 @@ func (s *Store) Close() error {
 	return s.db.Close()
 }
 
-func (s *Store) migrate() error {
-	stmts := []string{
-		`CREATE TABLE IF NOT EXISTS alerts (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			ts INTEGER NOT NULL,
-			priority TEXT,
-			group_name TEXT,
-			title TEXT,
-			dedup_key TEXT,
-			merge_key TEXT,
-			status TEXT,
-			channel TEXT,
-			dingtalk_errcode INTEGER,
-			dingtalk_errmsg TEXT,
-			payload_md TEXT,
-			created_at TEXT
-		);`,
-		`CREATE INDEX IF NOT EXISTS idx_alerts_ts ON alerts(ts);`,
-		`CREATE INDEX IF NOT EXISTS idx_alerts_status ON alerts(status);`,
-		`CREATE INDEX IF NOT EXISTS idx_alerts_group ON alerts(group_name);`,
-		`CREATE INDEX IF NOT EXISTS idx_alerts_dedup ON alerts(dedup_key);`,
-		`CREATE TABLE IF NOT EXISTS events (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			ts INTEGER NOT NULL,
-			type TEXT,
-			severity TEXT,
-			group_name TEXT,
-			title TEXT,
-			dedup_key TEXT,
-			merge_key TEXT,
-			evidence_json TEXT,
-			created_at TEXT
-		);`,
-		`CREATE INDEX IF NOT EXISTS idx_events_ts ON events(ts);`,
-		`CREATE INDEX IF NOT EXISTS idx_events_group ON events(group_name);`,
-		`CREATE TABLE IF NOT EXISTS market_snapshot (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			ts INTEGER NOT NULL,
-			symbol TEXT,
-			price REAL,
-			change_pct REAL,
-			volume REAL,
-			raw TEXT,
-			created_at TEXT
-		);`,
-		`CREATE INDEX IF NOT EXISTS idx_market_snapshot_ts ON market_snapshot(ts);`,
-		`CREATE INDEX IF NOT EXISTS idx_market_snapshot_symbol ON market_snapshot(symbol);`,
-		`CREATE TABLE IF NOT EXISTS plan (
-			date TEXT PRIMARY KEY,
-			content_json TEXT,
-			content_md TEXT,
-			confirmed INTEGER,
-			created_at TEXT
-		);`,
-	}
-	for _, stmt := range stmts {
-		if _, err := s.db.Exec(stmt); err != nil {
-			return fmt.Errorf("migrate: %w", err)
-		}
-	}
-	return nil
+func (s *Store) exec(query string, args ...any) (sql.Result, error) {
+	return s.db.Exec(s.dialect.rebind(query), args...)
+}
+
+func (s *Store) query(query string, args ...any) (*sql.Rows, error) {
+	return s.db.Query(s.dialect.rebind(query), args...)
+}
+
+func (s *Store) queryRow(query string, args ...any) *sql.Row {
+	return s.db.QueryRow(s.dialect.rebind(query), args...)
 }
 
 func (s *Store) InsertAlert(a AlertRecord) error {
@@ -168,7 +206,7 @@ func (s *Store) InsertAlert(a AlertRecord) error {
 	if a.CreatedAt == "" {
 		a.CreatedAt = time.Now().Format(time.RFC3339)
 	}
-	_, err := s.db.Exec(
+	_, err := s.exec(
 		`INSERT INTO alerts (ts, priority, group_name, title, dedup_key, merge_key, status, channel, dingtalk_errcode, dingtalk_errmsg, payload_md, created_at)
 		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		a.TS, a.Priority, a.GroupName, a.Title, a.DedupKey, a.MergeKey, a.Status, a.Channel, a.DingTalkErrCode, a.DingTalkErrMsg, a.PayloadMD, a.CreatedAt,
@@ -179,6 +217,81 @@ func (s *Store) InsertAlert(a AlertRecord) error {
 	return nil
 }
 
+// InsertAlertWAL records a pending merge batch entry or digest entry so it
+// survives a restart before it gets flushed.
+func (s *Store) InsertAlertWAL(rec AlertWALRecord) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	_, err := s.exec(
+		`INSERT INTO alert_wal (kind, key, group_name, req_json, enqueued_at, flush_at)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		rec.Kind, rec.Key, rec.GroupName, rec.ReqJSON, rec.EnqueuedAt, rec.FlushAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert alert wal: %w", err)
+	}
+	return nil
+}
+
+// QueryAlertWAL returns every pending WAL row, used at startup to replay
+// merges/digests that hadn't flushed before a restart.
+func (s *Store) QueryAlertWAL() ([]AlertWALRecord, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	rows, err := s.query(`SELECT id, kind, key, group_name, req_json, enqueued_at, flush_at FROM alert_wal ORDER BY id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("query alert wal: %w", err)
+	}
+	defer rows.Close()
+	var out []AlertWALRecord
+	for rows.Next() {
+		var rec AlertWALRecord
+		if err := rows.Scan(&rec.ID, &rec.Kind, &rec.Key, &rec.GroupName, &rec.ReqJSON, &rec.EnqueuedAt, &rec.FlushAt); err != nil {
+			return nil, fmt.Errorf("scan alert wal: %w", err)
+		}
+		out = append(out, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows alert wal: %w", err)
+	}
+	return out, nil
+}
+
+// DeleteAlertWAL removes every WAL row for (kind, key) once its batch has
+// flushed - key is a merge key for kind "merge" or a group name for kind
+// "digest".
+func (s *Store) DeleteAlertWAL(kind, key string) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	_, err := s.exec(`DELETE FROM alert_wal WHERE kind = ? AND key = ?`, kind, key)
+	if err != nil {
+		return fmt.Errorf("delete alert wal: %w", err)
+	}
+	return nil
+}
+
+// ReclaimOrphanedAlertWAL deletes WAL rows enqueued before cutoff that were
+// never flushed (e.g. a merge/digest window reconfigured shorter than a
+// row's remaining wait, or a bug that left a row behind). Returns the
+// number of rows removed.
+func (s *Store) ReclaimOrphanedAlertWAL(cutoff int64) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	res, err := s.exec(`DELETE FROM alert_wal WHERE enqueued_at < ?`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("reclaim alert wal: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected: %w", err)
+	}
+	return n, nil
+}
+
 func (s *Store) InsertEvent(e EventRecord) error {
 	if s == nil || s.db == nil {
 		return nil
@@ -186,7 +299,7 @@ func (s *Store) InsertEvent(e EventRecord) error {
 	if e.CreatedAt == "" {
 		e.CreatedAt = time.Now().Format(time.RFC3339)
 	}
-	_, err := s.db.Exec(
+	_, err := s.exec(
 		`INSERT INTO events (ts, type, severity, group_name, title, dedup_key, merge_key, evidence_json, created_at)
 		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		e.TS, e.Type, e.Severity, e.GroupName, e.Title, e.DedupKey, e.MergeKey, e.EvidenceJSON, e.CreatedAt,
@@ -204,7 +317,7 @@ func (s *Store) InsertEventReturnID(e EventRecord) (int64, error) {
 	if e.CreatedAt == "" {
 		e.CreatedAt = time.Now().Format(time.RFC3339)
 	}
-	res, err := s.db.Exec(
+	res, err := s.exec(
 		`INSERT INTO events (ts, type, severity, group_name, title, dedup_key, merge_key, evidence_json, created_at)
 		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		e.TS, e.Type, e.Severity, e.GroupName, e.Title, e.DedupKey, e.MergeKey, e.EvidenceJSON, e.CreatedAt,
@@ -242,7 +355,7 @@ func (s *Store) QueryAlertsByDate(date string, status string, group string, limi
 	query += " ORDER BY ts DESC LIMIT ? OFFSET ?"
 	args = append(args, limit, offset)
 
-	rows, err := s.db.Query(query, args...)
+	rows, err := s.query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query alerts: %w", err)
 	}
@@ -266,7 +379,7 @@ func (s *Store) QueryAlertsByDedupKey(key string) ([]AlertRecord, error) {
 	if s == nil || s.db == nil {
 		return nil, fmt.Errorf("store not initialized")
 	}
-	rows, err := s.db.Query(
+	rows, err := s.query(
 		`SELECT ts, priority, group_name, title, dedup_key, merge_key, status, channel, dingtalk_errcode, dingtalk_errmsg, payload_md, created_at
 		FROM alerts WHERE dedup_key = ? ORDER BY ts DESC`,
 		key,
@@ -317,7 +430,7 @@ func (s *Store) QueryEventsByDate(date string, eventType string, limit int, offs
 	query += " ORDER BY ts DESC LIMIT ? OFFSET ?"
 	args = append(args, limit, offset)
 
-	rows, err := s.db.Query(query, args...)
+	rows, err := s.query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query events: %w", err)
 	}
@@ -341,7 +454,7 @@ func (s *Store) GetEventByID(id int64) (*EventRecord, error) {
 	if s == nil || s.db == nil {
 		return nil, fmt.Errorf("store not initialized")
 	}
-	row := s.db.QueryRow(`SELECT id, ts, type, severity, group_name, title, dedup_key, merge_key, evidence_json, created_at FROM events WHERE id = ?`, id)
+	row := s.queryRow(`SELECT id, ts, type, severity, group_name, title, dedup_key, merge_key, evidence_json, created_at FROM events WHERE id = ?`, id)
 	var e EventRecord
 	if err := row.Scan(&e.ID, &e.TS, &e.Type, &e.Severity, &e.GroupName, &e.Title, &e.DedupKey, &e.MergeKey, &e.EvidenceJSON, &e.CreatedAt); err != nil {
 		return nil, fmt.Errorf("get event: %w", err)
@@ -356,10 +469,10 @@ func (s *Store) InsertMarketSnapshot(ms MarketSnapshot) error {
 	if ms.CreatedAt == "" {
 		ms.CreatedAt = time.Now().Format(time.RFC3339)
 	}
-	_, err := s.db.Exec(
-		`INSERT INTO market_snapshot (ts, symbol, price, change_pct, volume, raw, created_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		ms.TS, ms.Symbol, ms.Price, ms.ChangePct, ms.Volume, ms.Raw, ms.CreatedAt,
+	_, err := s.exec(
+		`INSERT INTO market_snapshot (ts, symbol, name, price, change_pct, volume, raw, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		ms.TS, ms.Symbol, ms.Name, ms.Price, ms.ChangePct, ms.Volume, ms.Raw, ms.CreatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("insert market snapshot: %w", err)
@@ -380,10 +493,10 @@ func (s *Store) QueryMarketSnapshots(symbol string, limit int, offset int) ([]Ma
 	if offset < 0 {
 		offset = 0
 	}
-	query := `SELECT ts, symbol, price, change_pct, volume, raw, created_at
+	query := `SELECT ts, symbol, name, price, change_pct, volume, raw, created_at
 		FROM market_snapshot WHERE symbol = ?
 		ORDER BY ts DESC LIMIT ? OFFSET ?`
-	rows, err := s.db.Query(query, symbol, limit, offset)
+	rows, err := s.query(query, symbol, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("query market snapshot: %w", err)
 	}
@@ -391,7 +504,7 @@ func (s *Store) QueryMarketSnapshots(symbol string, limit int, offset int) ([]Ma
 	var out []MarketSnapshot
 	for rows.Next() {
 		var ms MarketSnapshot
-		if err := rows.Scan(&ms.TS, &ms.Symbol, &ms.Price, &ms.ChangePct, &ms.Volume, &ms.Raw, &ms.CreatedAt); err != nil {
+		if err := rows.Scan(&ms.TS, &ms.Symbol, &ms.Name, &ms.Price, &ms.ChangePct, &ms.Volume, &ms.Raw, &ms.CreatedAt); err != nil {
 			return nil, fmt.Errorf("scan market snapshot: %w", err)
 		}
 		out = append(out, ms)
@@ -402,6 +515,135 @@ func (s *Store) QueryMarketSnapshots(symbol string, limit int, offset int) ([]Ma
 	return out, nil
 }
 
+// QueryMarketSnapshotsRange returns every snapshot for symbol between
+// startDate and endDate (inclusive, Asia/Shanghai calendar days), ordered
+// chronologically. Used by the backtest subsystem to replay a historical
+// window in the order it originally happened.
+func (s *Store) QueryMarketSnapshotsRange(symbol string, startDate, endDate string) ([]MarketSnapshot, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	start, _, err := dateRange(startDate)
+	if err != nil {
+		return nil, err
+	}
+	_, end, err := dateRange(endDate)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT ts, symbol, name, price, change_pct, volume, raw, created_at
+		FROM market_snapshot WHERE symbol = ? AND ts >= ? AND ts < ?
+		ORDER BY ts ASC`
+	rows, err := s.query(query, symbol, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("query market snapshot range: %w", err)
+	}
+	defer rows.Close()
+	var out []MarketSnapshot
+	for rows.Next() {
+		var ms MarketSnapshot
+		if err := rows.Scan(&ms.TS, &ms.Symbol, &ms.Name, &ms.Price, &ms.ChangePct, &ms.Volume, &ms.Raw, &ms.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan market snapshot: %w", err)
+		}
+		out = append(out, ms)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows market snapshot range: %w", err)
+	}
+	return out, nil
+}
+
+// CountEventsByType returns, for events between startDate and endDate
+// (inclusive, Asia/Shanghai calendar days), the number of events of each
+// Type. Used by the backtest subsystem to report per-rule trigger counts.
+func (s *Store) CountEventsByType(startDate, endDate string) (map[string]int, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	start, _, err := dateRange(startDate)
+	if err != nil {
+		return nil, err
+	}
+	_, end, err := dateRange(endDate)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.query(`SELECT type, COUNT(*) FROM events WHERE ts >= ? AND ts < ? GROUP BY type`, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("count events by type: %w", err)
+	}
+	defer rows.Close()
+	out := make(map[string]int)
+	for rows.Next() {
+		var t string
+		var c int
+		if err := rows.Scan(&t, &c); err != nil {
+			return nil, fmt.Errorf("scan event count: %w", err)
+		}
+		out[t] = c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows event count: %w", err)
+	}
+	return out, nil
+}
+
+func (s *Store) InsertNotifierDelivery(rec NotifierDeliveryRecord) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	if rec.CreatedAt == "" {
+		rec.CreatedAt = time.Now().Format(time.RFC3339)
+	}
+	_, err := s.exec(
+		`INSERT INTO notifier_deliveries (ts, channel, dedup_key, title, payload, status, error, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		rec.TS, rec.Channel, rec.DedupKey, rec.Title, rec.Payload, rec.Status, rec.Error, rec.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert notifier delivery: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) QueryNotifierDeliveries(channel string, limit int) ([]NotifierDeliveryRecord, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	if limit <= 0 {
+		limit = 200
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+	query := `SELECT ts, channel, dedup_key, title, payload, status, error, created_at FROM notifier_deliveries`
+	args := []any{}
+	if channel != "" {
+		query += " WHERE channel = ?"
+		args = append(args, channel)
+	}
+	query += " ORDER BY ts DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query notifier deliveries: %w", err)
+	}
+	defer rows.Close()
+	var out []NotifierDeliveryRecord
+	for rows.Next() {
+		var rec NotifierDeliveryRecord
+		if err := rows.Scan(&rec.TS, &rec.Channel, &rec.DedupKey, &rec.Title, &rec.Payload, &rec.Status, &rec.Error, &rec.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan notifier delivery: %w", err)
+		}
+		out = append(out, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows notifier delivery: %w", err)
+	}
+	return out, nil
+}
+
 func (s *Store) UpsertPlan(rec PlanRecord) error {
 	if s == nil || s.db == nil {
 		return nil
@@ -413,7 +655,7 @@ func (s *Store) UpsertPlan(rec PlanRecord) error {
 	if rec.Confirmed {
 		confirmed = 1
 	}
-	_, err := s.db.Exec(
+	_, err := s.exec(
 		`INSERT INTO plan (date, content_json, content_md, confirmed, created_at)
 		 VALUES (?, ?, ?, ?, ?)
 		 ON CONFLICT(date) DO UPDATE SET content_json=excluded.content_json, content_md=excluded.content_md, confirmed=excluded.confirmed, created_at=excluded.created_at`,
@@ -429,7 +671,7 @@ func (s *Store) GetPlan(date string) (*PlanRecord, error) {
 	if s == nil || s.db == nil {
 		return nil, fmt.Errorf("store not initialized")
 	}
-	row := s.db.QueryRow(`SELECT date, content_json, content_md, confirmed, created_at FROM plan WHERE date = ?`, date)
+	row := s.queryRow(`SELECT date, content_json, content_md, confirmed, created_at FROM plan WHERE date = ?`, date)
 	var rec PlanRecord
 	var confirmed int
 	if err := row.Scan(&rec.Date, &rec.ContentJSON, &rec.ContentMD, &confirmed, &rec.CreatedAt); err != nil {
@@ -443,13 +685,279 @@ func (s *Store) ConfirmPlan(date string) error {
 	if s == nil || s.db == nil {
 		return nil
 	}
-	_, err := s.db.Exec(`UPDATE plan SET confirmed = 1 WHERE date = ?`, date)
+	_, err := s.exec(`UPDATE plan SET confirmed = 1 WHERE date = ?`, date)
 	if err != nil {
 		return fmt.Errorf("confirm plan: %w", err)
 	}
 	return nil
 }
 
+// InsertOrder records a new order, or returns the ID of the existing one
+// if (Broker, ExtID) was already recorded — callers (the market poller)
+// can call this on every tick a trigger is crossed without worrying about
+// duplicate orders.
+func (s *Store) InsertOrder(o OrderRecord) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	if o.CreatedAt == "" {
+		o.CreatedAt = time.Now().Format(time.RFC3339)
+	}
+	_, err := s.exec(
+		`INSERT INTO orders (ts, plan_date, trade_item_symbol, symbol, side, qty, price, status, broker, ext_id, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(broker, ext_id) DO NOTHING`,
+		o.TS, o.PlanDate, o.TradeItemSymbol, o.Symbol, o.Side, o.Qty, o.Price, o.Status, o.Broker, o.ExtID, o.CreatedAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert order: %w", err)
+	}
+	row := s.queryRow(`SELECT id FROM orders WHERE broker = ? AND ext_id = ?`, o.Broker, o.ExtID)
+	var id int64
+	if err := row.Scan(&id); err != nil {
+		return 0, fmt.Errorf("get order id: %w", err)
+	}
+	return id, nil
+}
+
+// RecordExecution records a fill against orderID, or is a no-op if
+// (Broker, ExtID) was already recorded, then folds the fill into
+// positions via UpsertPosition (weighted-average entry, realized PnL on
+// the closing side of a position).
+func (s *Store) RecordExecution(e ExecutionRecord) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	if e.CreatedAt == "" {
+		e.CreatedAt = time.Now().Format(time.RFC3339)
+	}
+	res, err := s.exec(
+		`INSERT INTO executions (order_id, ts, symbol, side, qty, price, broker, ext_id, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(broker, ext_id) DO NOTHING`,
+		e.OrderID, e.TS, e.Symbol, e.Side, e.Qty, e.Price, e.Broker, e.ExtID, e.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("record execution: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return nil
+	}
+
+	pos, err := s.getPosition(e.Symbol)
+	if err != nil {
+		return fmt.Errorf("get position: %w", err)
+	}
+	qty, avgPrice, realizedPnl := pos.Qty, pos.AvgPrice, pos.RealizedPnl
+	switch e.Side {
+	case "buy":
+		newQty := qty + e.Qty
+		if newQty != 0 {
+			avgPrice = (qty*avgPrice + e.Qty*e.Price) / newQty
+		}
+		qty = newQty
+	case "sell":
+		closing := e.Qty
+		if closing > qty {
+			closing = qty
+		}
+		realizedPnl += closing * (e.Price - avgPrice)
+		qty -= e.Qty
+		if qty <= 0 {
+			qty = 0
+			avgPrice = 0
+		}
+	}
+	return s.UpsertPosition(PositionRecord{
+		Symbol:      e.Symbol,
+		Qty:         qty,
+		AvgPrice:    avgPrice,
+		RealizedPnl: realizedPnl,
+	})
+}
+
+func (s *Store) getPosition(symbol string) (PositionRecord, error) {
+	row := s.queryRow(`SELECT symbol, qty, avg_price, realized_pnl, updated_at FROM positions WHERE symbol = ?`, symbol)
+	var rec PositionRecord
+	err := row.Scan(&rec.Symbol, &rec.Qty, &rec.AvgPrice, &rec.RealizedPnl, &rec.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return PositionRecord{Symbol: symbol}, nil
+	}
+	if err != nil {
+		return PositionRecord{}, err
+	}
+	return rec, nil
+}
+
+// UpsertPosition overwrites symbol's running position, e.g. after a fill
+// or a manual adjustment.
+func (s *Store) UpsertPosition(rec PositionRecord) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	rec.UpdatedAt = time.Now().Format(time.RFC3339)
+	_, err := s.exec(
+		`INSERT INTO positions (symbol, qty, avg_price, realized_pnl, updated_at)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(symbol) DO UPDATE SET qty=excluded.qty, avg_price=excluded.avg_price, realized_pnl=excluded.realized_pnl, updated_at=excluded.updated_at`,
+		rec.Symbol, rec.Qty, rec.AvgPrice, rec.RealizedPnl, rec.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert position: %w", err)
+	}
+	return nil
+}
+
+// QueryPositions returns every symbol with a recorded position, including
+// flat ones, so the UI can show the full plan->order->fill lineage.
+func (s *Store) QueryPositions() ([]PositionRecord, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	rows, err := s.query(`SELECT symbol, qty, avg_price, realized_pnl, updated_at FROM positions ORDER BY symbol`)
+	if err != nil {
+		return nil, fmt.Errorf("query positions: %w", err)
+	}
+	defer rows.Close()
+
+	var out []PositionRecord
+	for rows.Next() {
+		var rec PositionRecord
+		if err := rows.Scan(&rec.Symbol, &rec.Qty, &rec.AvgPrice, &rec.RealizedPnl, &rec.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan position: %w", err)
+		}
+		out = append(out, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows position: %w", err)
+	}
+	return out, nil
+}
+
+// SilenceRecord is a persisted alert.Silence: MatchersJSON holds the
+// marshaled []alert.Matcher, since the store package doesn't depend on
+// alert and so can't reference that type directly.
+type SilenceRecord struct {
+	ID           int64  `json:"id"`
+	MatchersJSON string `json:"matchers_json"`
+	StartsAt     int64  `json:"starts_at"`
+	EndsAt       int64  `json:"ends_at"`
+	CreatedBy    string `json:"created_by"`
+	Comment      string `json:"comment"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// InsertSilence records a new silence and returns its assigned ID.
+func (s *Store) InsertSilence(rec SilenceRecord) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	if rec.CreatedAt == "" {
+		rec.CreatedAt = time.Now().Format(time.RFC3339)
+	}
+	res, err := s.exec(
+		`INSERT INTO silences (matchers_json, starts_at, ends_at, created_by, comment, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		rec.MatchersJSON, rec.StartsAt, rec.EndsAt, rec.CreatedBy, rec.Comment, rec.CreatedAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert silence: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("last insert id: %w", err)
+	}
+	return id, nil
+}
+
+// GetSilence returns the silence with id, or ok=false if none exists
+// (already deleted, or never existed).
+func (s *Store) GetSilence(id int64) (SilenceRecord, bool, error) {
+	if s == nil || s.db == nil {
+		return SilenceRecord{}, false, fmt.Errorf("store not initialized")
+	}
+	row := s.queryRow(`SELECT id, matchers_json, starts_at, ends_at, created_by, comment, created_at FROM silences WHERE id = ?`, id)
+	var rec SilenceRecord
+	err := row.Scan(&rec.ID, &rec.MatchersJSON, &rec.StartsAt, &rec.EndsAt, &rec.CreatedBy, &rec.Comment, &rec.CreatedAt)
+	if err == sql.ErrNoRows {
+		return SilenceRecord{}, false, nil
+	}
+	if err != nil {
+		return SilenceRecord{}, false, fmt.Errorf("get silence: %w", err)
+	}
+	return rec, true, nil
+}
+
+// QuerySilences returns every silence, active or expired, newest first —
+// used by the CRUD listing endpoint.
+func (s *Store) QuerySilences() ([]SilenceRecord, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	rows, err := s.query(`SELECT id, matchers_json, starts_at, ends_at, created_by, comment, created_at FROM silences ORDER BY id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("query silences: %w", err)
+	}
+	defer rows.Close()
+
+	var out []SilenceRecord
+	for rows.Next() {
+		var rec SilenceRecord
+		if err := rows.Scan(&rec.ID, &rec.MatchersJSON, &rec.StartsAt, &rec.EndsAt, &rec.CreatedBy, &rec.Comment, &rec.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan silence: %w", err)
+		}
+		out = append(out, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows silence: %w", err)
+	}
+	return out, nil
+}
+
+// QueryActiveSilences returns every silence whose window contains now
+// (unix seconds) — used by alert.Service to match incoming alerts and by
+// its sweeper to detect newly-expired silences.
+func (s *Store) QueryActiveSilences(now int64) ([]SilenceRecord, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	rows, err := s.query(`SELECT id, matchers_json, starts_at, ends_at, created_by, comment, created_at FROM silences WHERE starts_at <= ? AND ends_at > ? ORDER BY id DESC`, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("query active silences: %w", err)
+	}
+	defer rows.Close()
+
+	var out []SilenceRecord
+	for rows.Next() {
+		var rec SilenceRecord
+		if err := rows.Scan(&rec.ID, &rec.MatchersJSON, &rec.StartsAt, &rec.EndsAt, &rec.CreatedBy, &rec.Comment, &rec.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan silence: %w", err)
+		}
+		out = append(out, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows silence: %w", err)
+	}
+	return out, nil
+}
+
+// DeleteSilence removes a silence by ID, e.g. when a maintenance window
+// ends early. Returns false if no row had that ID.
+func (s *Store) DeleteSilence(id int64) (bool, error) {
+	if s == nil || s.db == nil {
+		return false, fmt.Errorf("store not initialized")
+	}
+	res, err := s.exec(`DELETE FROM silences WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("delete silence: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
 func dateRange(date string) (int64, int64, error) {
 	loc, err := time.LoadLocation("Asia/Shanghai")
 	if err != nil {